@@ -17,7 +17,7 @@ PlaintText x                                                               Key k
 
 	Round 2 to nr-2 ...
 
-	Round 9
+	Round nr-1
 		Byte Substitution Layer (SubBytes)
 
 		ShiftRows Layer --+
@@ -26,7 +26,7 @@ PlaintText x                                                               Key k
 
 		Key Addtion Layer (AddRoundKey)  <------------ knr-1 ---------- Transform nr-1
 
-	Round 10 (final round, no MixColumns)
+	Round nr (final round, no MixColumns)
 
 		Byte Substitution Layer (SubBytes)
 
@@ -36,6 +36,12 @@ PlaintText x                                                               Key k
 
 CipherText y=AES(x)
 
+	nr depende do tamanho da chave:
+
+		AES-128: Nk = 4, nr = 10
+		AES-192: Nk = 6, nr = 12
+		AES-256: Nk = 8, nr = 14
+
 */
 
 package main
@@ -45,11 +51,178 @@ import (
 )
 
 const Nb = 4 // número de colunas do state (sempre 4 no AES)
-const Nk = 4 // número de palavras da chave (4 para AES-128)
-const Nr = 10 // número de rodadas (10 para AES-128)
+
+// MaxNb é o maior número de colunas do state do Rijndael (aes_rijndael.go).
+const MaxNb = 8
 
 type State [4][4]byte // 4 linhas (bytes) x 4 colunas
 
+// rows devolve as linhas do state como slices, no formato usado pelas
+// transformações genéricas (que valem para qualquer Nb).
+func (s *State) rows() [4][]byte {
+	return [4][]byte{s[0][:], s[1][:], s[2][:], s[3][:]}
+}
+
+// Nomes das etapas reportadas a um Tracer
+const (
+	StepInput         = "input"
+	StepStart         = "start"
+	StepSubBytes      = "SubBytes"
+	StepShiftRows     = "ShiftRows"
+	StepMixColumns    = "MixColumns"
+	StepAddRoundKey   = "AddRoundKey"
+	StepInvSubBytes   = "InvSubBytes"
+	StepInvShiftRows  = "InvShiftRows"
+	StepInvMixColumns = "InvMixColumns"
+	StepOutput        = "output"
+)
+
+// TraceStep é uma fotografia do state logo após uma etapa de uma rodada.
+// RoundKey só é preenchida na etapa AddRoundKey.
+type TraceStep struct {
+	Round    int
+	Step     string
+	State    State
+	RoundKey []byte
+}
+
+/*
+Tracer: Recebe o state após cada transformação de EncryptBlockTrace e
+DecryptBlockTrace, permitindo reproduzir o passo a passo do FIPS-197,
+apêndice B. Veja TraceRecorder em aes_trace.go.
+*/
+type Tracer interface {
+	Trace(step TraceStep)
+}
+
+/*
+roundHook: Chamada por encryptRounds e decryptRounds após cada etapa, com
+as linhas do state e, em AddRoundKey, a chave da rodada. Pode alterar o
+state: é assim que o tracer observa e o simulador de falhas (aes_dfa.go)
+injeta erros sem duplicar o laço das rodadas.
+*/
+type roundHook func(round int, step string, rows [4][]byte, roundKey []byte)
+
+/*
+callHook: Chama o hook sobre uma cópia do state (view) e copia o resultado
+de volta. Como rows nunca chega ao hook, o state de encryptBlock fica na
+pilha e Encrypt/Decrypt não alocam; view só existe quando há hook.
+*/
+func callHook(hook roundHook, view, rows [4][]byte, round int, step string, roundKey []byte) {
+	if hook == nil {
+		return
+	}
+	for i := range rows {
+		copy(view[i], rows[i])
+	}
+	hook(round, step, view, roundKey)
+	for i := range rows {
+		copy(rows[i], view[i])
+	}
+}
+
+// hookView aloca o state usado por callHook, ou nada se hook for nil.
+func hookView(hook roundHook, nb int) [4][]byte {
+	if hook == nil {
+		return [4][]byte{}
+	}
+	return new(RijndaelState).rows(nb)
+}
+
+// tracerHook adapta um Tracer (state 4x4 do AES) para roundHook; nil continua nil.
+func tracerHook(tracer Tracer) roundHook {
+	if tracer == nil {
+		return nil
+	}
+	return func(round int, step string, rows [4][]byte, roundKey []byte) {
+		var state State
+		for row := range state {
+			copy(state[row][:], rows[row])
+		}
+		tracer.Trace(TraceStep{Round: round, Step: step, State: state, RoundKey: append([]byte(nil), roundKey...)})
+	}
+}
+
+// AES guarda a chave expandida junto com os parâmetros que dependem do
+// tamanho da chave: Nk (palavras da chave) e Nr (número de rodadas).
+type AES struct {
+	Nk          int
+	Nr          int
+	expandedKey [][4]byte
+	roundKeys   []byte // expandedKey em bytes, achatada uma vez em New
+}
+
+// KeySizeError indica uma chave com tamanho diferente de 16, 24 ou 32 bytes.
+type KeySizeError int
+
+func (k KeySizeError) Error() string {
+	return fmt.Sprintf("aes: tamanho de chave inválido: %d bytes (use 16, 24 ou 32)", int(k))
+}
+
+/*
+New: Cria uma instância do AES a partir de uma chave de 16, 24 ou 32 bytes.
+
+	O tamanho da chave define Nk = len(key)/4 e Nr = Nk + 6
+	(FIPS-197, seção 5, figura 4):
+
+		+---------+-----+-----+
+		|         | Nk  | Nr  |
+		+---------+-----+-----+
+		| AES-128 |  4  | 10  |
+		| AES-192 |  6  | 12  |
+		| AES-256 |  8  | 14  |
+		+---------+-----+-----+
+*/
+func New(key []byte) (*AES, error) {
+	switch len(key) {
+	case 16, 24, 32:
+	default:
+		return nil, KeySizeError(len(key))
+	}
+
+	nk := len(key) / 4
+	w := KeyExpansion(key)
+	return &AES{
+		Nk:          nk,
+		Nr:          nk + 6,
+		expandedKey: w,
+		roundKeys:   flattenKey(w),
+	}, nil
+}
+
+/*
+NewWithRounds: Cria um AES reduzido, com apenas `rounds` rodadas (1..Nr).
+
+	Usado nos exercícios de criptoanálise (ataque square, DFA). A última
+	rodada continua sem MixColumns, como no AES completo. A chave expandida
+	é truncada em Nb*(rounds+1) palavras, então EncryptBlock, DecryptBlock,
+	Encrypt/Decrypt e o tracer passam a usar só essas rodadas.
+*/
+func NewWithRounds(key []byte, rounds int) (*AES, error) {
+	a, err := New(key)
+	if err != nil {
+		return nil, err
+	}
+	if rounds < 1 || rounds > a.Nr {
+		return nil, fmt.Errorf("aes: número de rodadas inválido: %d (use 1 a %d)", rounds, a.Nr)
+	}
+
+	a.Nr = rounds
+	a.expandedKey = a.expandedKey[:Nb*(rounds+1)]
+	a.roundKeys = a.roundKeys[:4*Nb*(rounds+1)]
+	return a, nil
+}
+
+// EncryptBlock cifra um bloco de 16 bytes com a chave expandida da instância.
+func (a *AES) EncryptBlock(input []byte) []byte {
+	return EncryptBlock(input, a.expandedKey)
+}
+
+// DecryptBlock decifra um bloco de 16 bytes com a chave expandida da instância.
+func (a *AES) DecryptBlock(input []byte) []byte {
+	return DecryptBlock(input, a.expandedKey)
+}
+
 // S-box padrão AES
 var sbox = [256]byte{
 	0x63, 0x7c, 0x77, 0x7b, 0xf2, 0x6b, 0x6f, 0xc5,
@@ -121,73 +294,78 @@ var invSbox = [256]byte{
 	0xe1, 0x69, 0x14, 0x63, 0x55, 0x21, 0x0c, 0x7d,
 }
 
-
 // Rcon: constantes usadas na expansão da chave
 var rcon = [10]byte{0x01, 0x02, 0x04, 0x08, 0x10, 0x20, 0x40, 0x80, 0x1B, 0x36}
 
+/*
+shiftOffsets[Nb]: Deslocamento C_i de cada linha em ShiftRows. No AES (Nb =
+4) são 0, 1, 2 e 3; as demais linhas são do Rijndael (aes_rijndael.go).
+*/
+var shiftOffsets = [MaxNb + 1][4]int{
+	4: {0, 1, 2, 3},
+	5: {0, 1, 2, 3},
+	6: {0, 1, 2, 3},
+	7: {0, 1, 2, 4},
+	8: {0, 1, 3, 4},
+}
+
 func SubBytes(state *State) {
-	for row := 0; row < 4; row++ {
-		for col := 0; col < 4; col++ {
-			state[row][col] = sbox[state[row][col]]
-		}
-	}
+	subBytes(state.rows(), &sbox)
 }
 
 func InvSubBytes(state *State) {
-	for row := 0; row < 4; row++ {
-		for col := 0; col < 4; col++ {
-			state[row][col] = invSbox[state[row][col]]
-		}
-	}
+	subBytes(state.rows(), &invSbox)
 }
 
 func ShiftRows(state *State) {
-	var tmp [4]byte
-
-	// Linha 1: shift 1
-	for i := 0; i < 4; i++ {
-		tmp[i] = state[1][(i+1)%4]
-	}
-	copy(state[1][:], tmp[:])
+	shiftRows(state.rows(), false)
+}
 
-	// Linha 2: shift 2
-	for i := 0; i < 4; i++ {
-		tmp[i] = state[2][(i+2)%4]
-	}
-	copy(state[2][:], tmp[:])
+func InvShiftRows(state *State) {
+	shiftRows(state.rows(), true)
+}
 
-	// Linha 3: shift 3
-	for i := 0; i < 4; i++ {
-		tmp[i] = state[3][(i+3)%4]
-	}
-	copy(state[3][:], tmp[:])
+func MixColumns(state *State) {
+	mixColumns(state.rows())
 }
 
-func InvShiftRows(state *State) {
-	var tmp [4]byte
+func InvMixColumns(state *State) {
+	invMixColumns(state.rows())
+}
 
-	// Linha 1: shift 1 para direita
-	for i := 0; i < 4; i++ {
-		tmp[i] = state[1][(i+3)%4]
-	}
-	copy(state[1][:], tmp[:])
+func AddRoundKey(state *State, roundKey []byte) {
+	addRoundKey(state.rows(), roundKey)
+}
 
-	// Linha 2: shift 2 para direita
-	for i := 0; i < 4; i++ {
-		tmp[i] = state[2][(i+2)%4]
+// subBytes substitui cada byte do state pela tabela (S-box ou inversa).
+func subBytes(rows [4][]byte, table *[256]byte) {
+	for _, row := range rows {
+		for col := range row {
+			row[col] = table[row[col]]
+		}
 	}
-	copy(state[2][:], tmp[:])
+}
 
-	// Linha 3: shift 3 para direita
-	for i := 0; i < 4; i++ {
-		tmp[i] = state[3][(i+1)%4]
+// shiftRows desloca a linha i por C_i posições à esquerda (à direita, se inverse).
+func shiftRows(rows [4][]byte, inverse bool) {
+	nb := len(rows[0])
+	offsets := shiftOffsets[nb]
+	var tmp [MaxNb]byte
+
+	for row := 1; row < 4; row++ {
+		for col := 0; col < nb; col++ {
+			if inverse {
+				tmp[(col+offsets[row])%nb] = rows[row][col]
+			} else {
+				tmp[col] = rows[row][(col+offsets[row])%nb]
+			}
+		}
+		copy(rows[row], tmp[:nb])
 	}
-	copy(state[3][:], tmp[:])
 }
 
-
 func xtime(x byte) byte {
-	if x & 0x80 != 0 {
+	if x&0x80 != 0 {
 		return (x << 1) ^ 0x1b
 	}
 	return x << 1
@@ -221,41 +399,40 @@ func mulInv(x, y byte) byte {
 	}
 }
 
-func MixColumns(state *State) {
-	for col := 0; col < 4; col++ {
-		s0 := state[0][col]
-		s1 := state[1][col]
-		s2 := state[2][col]
-		s3 := state[3][col]
-
-		state[0][col] = mul(s0, 0x02) ^ mul(s1, 0x03) ^ s2 ^ s3
-		state[1][col] = s0 ^ mul(s1, 0x02) ^ mul(s2, 0x03) ^ s3
-		state[2][col] = s0 ^ s1 ^ mul(s2, 0x02) ^ mul(s3, 0x03)
-		state[3][col] = mul(s0, 0x03) ^ s1 ^ s2 ^ mul(s3, 0x02)
+// mixColumns multiplica cada uma das Nb colunas pela matriz do MixColumns.
+func mixColumns(rows [4][]byte) {
+	for col := range rows[0] {
+		s0 := rows[0][col]
+		s1 := rows[1][col]
+		s2 := rows[2][col]
+		s3 := rows[3][col]
+
+		rows[0][col] = mul(s0, 0x02) ^ mul(s1, 0x03) ^ s2 ^ s3
+		rows[1][col] = s0 ^ mul(s1, 0x02) ^ mul(s2, 0x03) ^ s3
+		rows[2][col] = s0 ^ s1 ^ mul(s2, 0x02) ^ mul(s3, 0x03)
+		rows[3][col] = mul(s0, 0x03) ^ s1 ^ s2 ^ mul(s3, 0x02)
 	}
 }
 
-
-
-func InvMixColumns(state *State) {
-	for col := 0; col < 4; col++ {
-		s0 := state[0][col]
-		s1 := state[1][col]
-		s2 := state[2][col]
-		s3 := state[3][col]
-
-		state[0][col] = mulInv(s0, 0x0e) ^ mulInv(s1, 0x0b) ^ mulInv(s2, 0x0d) ^ mulInv(s3, 0x09)
-		state[1][col] = mulInv(s0, 0x09) ^ mulInv(s1, 0x0e) ^ mulInv(s2, 0x0b) ^ mulInv(s3, 0x0d)
-		state[2][col] = mulInv(s0, 0x0d) ^ mulInv(s1, 0x09) ^ mulInv(s2, 0x0e) ^ mulInv(s3, 0x0b)
-		state[3][col] = mulInv(s0, 0x0b) ^ mulInv(s1, 0x0d) ^ mulInv(s2, 0x09) ^ mulInv(s3, 0x0e)
+func invMixColumns(rows [4][]byte) {
+	for col := range rows[0] {
+		s0 := rows[0][col]
+		s1 := rows[1][col]
+		s2 := rows[2][col]
+		s3 := rows[3][col]
+
+		rows[0][col] = mulInv(s0, 0x0e) ^ mulInv(s1, 0x0b) ^ mulInv(s2, 0x0d) ^ mulInv(s3, 0x09)
+		rows[1][col] = mulInv(s0, 0x09) ^ mulInv(s1, 0x0e) ^ mulInv(s2, 0x0b) ^ mulInv(s3, 0x0d)
+		rows[2][col] = mulInv(s0, 0x0d) ^ mulInv(s1, 0x09) ^ mulInv(s2, 0x0e) ^ mulInv(s3, 0x0b)
+		rows[3][col] = mulInv(s0, 0x0b) ^ mulInv(s1, 0x0d) ^ mulInv(s2, 0x09) ^ mulInv(s3, 0x0e)
 	}
 }
 
-
-func AddRoundKey(state *State, roundKey []byte) {
-	for col := 0; col < 4; col++ {
+// addRoundKey faz o XOR da chave da rodada (4*Nb bytes, coluna a coluna).
+func addRoundKey(rows [4][]byte, roundKey []byte) {
+	for col := range rows[0] {
 		for row := 0; row < 4; row++ {
-			state[row][col] ^= roundKey[col*4+row]
+			rows[row][col] ^= roundKey[col*4+row]
 		}
 	}
 }
@@ -265,107 +442,223 @@ func RotWord(word [4]byte) [4]byte {
 }
 
 func SubWord(word [4]byte) [4]byte {
-	return [4]byte{
-		sbox[word[0]],
-		sbox[word[1]],
-		sbox[word[2]],
-		sbox[word[3]],
-	}
+	return subWord(word, &sbox)
 }
 
-func KeyExpansion(key []byte) [Nb*(Nr+1)][4]byte {
-	var w [Nb*(Nr+1)][4]byte
+func subWord(word [4]byte, s *[256]byte) [4]byte {
+	return [4]byte{s[word[0]], s[word[1]], s[word[2]], s[word[3]]}
+}
+
+/*
+KeyExpansion: Expande a chave em Nb*(Nr+1) palavras de 4 bytes.
+
+	Nk e Nr são derivados do tamanho da chave (16, 24 ou 32 bytes).
+	Para chaves de 256 bits (Nk = 8) existe um SubWord extra quando
+	i%Nk == 4, sem RotWord e sem Rcon (FIPS-197, seção 5.2).
+*/
+func KeyExpansion(key []byte) [][4]byte {
+	return expandKey(key, Nb, SubWord)
+}
+
+/*
+expandKey: KeyExpansion para nb colunas e SubWord quaisquer.
+
+	Gera nb*(Nr+1) palavras, com Nr = max(nb, Nk) + 6. Com nb = 8 e Nk = 4
+	são 120 palavras, o que exige 29 constantes de rodada em vez das 10 do
+	AES; por isso Rcon é gerada por xtime a partir de 0x01. O SubWord extra
+	em i%Nk == 4 vale para Nk > 6 (224 e 256 bits).
+
+	subWord é a única etapa que muda entre as variantes: SubWord (tabela do
+	AES), a S-box do Rijndael parametrizado ou o circuito em tempo constante
+	de aes_bitsliced.go.
+*/
+func expandKey(key []byte, nb int, subWord func([4]byte) [4]byte) [][4]byte {
+	nk := len(key) / 4
+	nr := max(nb, nk) + 6
+	w := make([][4]byte, nb*(nr+1))
 
 	// Copia chave original
-	for i := 0; i < Nk; i++ {
+	for i := 0; i < nk; i++ {
 		w[i] = [4]byte{key[4*i], key[4*i+1], key[4*i+2], key[4*i+3]}
 	}
 
-	for i := Nk; i < Nb*(Nr+1); i++ {
+	rc := byte(0x01)
+	for i := nk; i < len(w); i++ {
 		temp := w[i-1]
-		if i%Nk == 0 {
-			temp = SubWord(RotWord(temp))
-			temp[0] ^= rcon[(i/Nk)-1]
+		if i%nk == 0 {
+			temp = subWord(RotWord(temp))
+			temp[0] ^= rc
+			rc = xtime(rc)
+		} else if nk > 6 && i%nk == 4 {
+			temp = subWord(temp)
 		}
 		for j := 0; j < 4; j++ {
-			w[i][j] = w[i-Nk][j] ^ temp[j]
+			w[i][j] = w[i-nk][j] ^ temp[j]
 		}
 	}
 
 	return w
 }
 
+// flattenKey junta palavras da chave expandida em bytes, na ordem de addRoundKey.
 func flattenKey(words [][4]byte) []byte {
-	out := make([]byte, 16)
-	for i := 0; i < 4; i++ {
+	out := make([]byte, 4*len(words))
+	for i := range words {
 		copy(out[i*4:(i+1)*4], words[i][:])
 	}
 	return out
 }
 
-func EncryptBlock(input []byte, expandedKey [Nb*(Nr+1)][4]byte) []byte {
+// numRounds deduz Nr a partir do tamanho da chave expandida: Nb*(Nr+1) palavras.
+func numRounds(expandedKey [][4]byte) int {
+	return len(expandedKey)/Nb - 1
+}
+
+// loadState copia o bloco para o state, coluna a coluna.
+func loadState(rows [4][]byte, src []byte) {
+	for i := 0; i < 4*len(rows[0]); i++ {
+		rows[i%4][i/4] = src[i]
+	}
+}
+
+// storeState copia o state para o bloco, coluna a coluna.
+func storeState(dst []byte, rows [4][]byte) {
+	for i := 0; i < 4*len(rows[0]); i++ {
+		dst[i] = rows[i%4][i/4]
+	}
+}
+
+func EncryptBlock(input []byte, expandedKey [][4]byte) []byte {
+	output := make([]byte, 16)
+	encryptBlock(output, input, flattenKey(expandedKey), nil)
+	return output
+}
+
+// EncryptBlockTrace é igual a EncryptBlock, mas reporta cada etapa ao tracer.
+func EncryptBlockTrace(input []byte, expandedKey [][4]byte, tracer Tracer) []byte {
+	output := make([]byte, 16)
+	encryptBlock(output, input, flattenKey(expandedKey), tracerHook(tracer))
+	return output
+}
+
+// encryptBlock cifra src e escreve o resultado em dst (dst e src podem ser o
+// mesmo slice, pois o state é uma cópia local). roundKeys é a chave expandida
+// já achatada por flattenKey; hook pode ser nil.
+func encryptBlock(dst, src []byte, roundKeys []byte, hook roundHook) {
 	var state State
+	rows := state.rows()
+	loadState(rows, src)
+	encryptRounds(rows, roundKeys, &sbox, hook)
+	storeState(dst, rows)
+}
 
-	// Copia input para state
-	for i := 0; i < 16; i++ {
-		state[i%4][i/4] = input[i]
+/*
+encryptRounds: As Nr rodadas da cifragem sobre um state de Nb colunas, com
+Nb = len(rows[0]) e Nr = len(roundKeys)/(4*Nb) - 1. É o mesmo laço para o
+AES (Nb = 4) e para o Rijndael, que só troca Nb e a S-box. As chaves de
+rodada são fatias de roundKeys, sem alocação por rodada.
+*/
+func encryptRounds(rows [4][]byte, roundKeys []byte, s *[256]byte, hook roundHook) {
+	n := 4 * len(rows[0])
+	nr := len(roundKeys)/n - 1
+	view := hookView(hook, len(rows[0]))
+	step := func(round int, name string, roundKey []byte) {
+		callHook(hook, view, rows, round, name, roundKey)
 	}
+	step(0, StepInput, nil)
 
 	// Rodada inicial
-	AddRoundKey(&state, flattenKey(expandedKey[0:4]))
-
-	// 9 rodadas principais
-	for round := 1; round < Nr; round++ {
-		SubBytes(&state)
-		ShiftRows(&state)
-		MixColumns(&state)
-		AddRoundKey(&state, flattenKey(expandedKey[round*4 : (round+1)*4]))
+	roundKey := roundKeys[0:n]
+	addRoundKey(rows, roundKey)
+	step(0, StepAddRoundKey, roundKey)
+
+	// Nr-1 rodadas principais
+	for round := 1; round < nr; round++ {
+		step(round, StepStart, nil)
+		subBytes(rows, s)
+		step(round, StepSubBytes, nil)
+		shiftRows(rows, false)
+		step(round, StepShiftRows, nil)
+		mixColumns(rows)
+		step(round, StepMixColumns, nil)
+		roundKey = roundKeys[round*n : (round+1)*n]
+		addRoundKey(rows, roundKey)
+		step(round, StepAddRoundKey, roundKey)
 	}
 
 	// Rodada final (sem MixColumns)
-	SubBytes(&state)
-	ShiftRows(&state)
-	AddRoundKey(&state, flattenKey(expandedKey[Nr*4 : (Nr+1)*4]))
+	step(nr, StepStart, nil)
+	subBytes(rows, s)
+	step(nr, StepSubBytes, nil)
+	shiftRows(rows, false)
+	step(nr, StepShiftRows, nil)
+	roundKey = roundKeys[nr*n : (nr+1)*n]
+	addRoundKey(rows, roundKey)
+	step(nr, StepAddRoundKey, roundKey)
+	step(nr, StepOutput, nil)
+}
 
-	// Copia state para output
+func DecryptBlock(input []byte, expandedKey [][4]byte) []byte {
 	output := make([]byte, 16)
-	for i := 0; i < 16; i++ {
-		output[i] = state[i%4][i/4]
-	}
-
+	decryptBlock(output, input, flattenKey(expandedKey), nil)
 	return output
 }
 
+// DecryptBlockTrace é igual a DecryptBlock, mas reporta cada etapa ao tracer.
+// As rodadas são numeradas como na cifragem, em ordem decrescente (Nr até 0).
+func DecryptBlockTrace(input []byte, expandedKey [][4]byte, tracer Tracer) []byte {
+	output := make([]byte, 16)
+	decryptBlock(output, input, flattenKey(expandedKey), tracerHook(tracer))
+	return output
+}
 
-
-func DecryptBlock(input []byte, expandedKey [Nb*(Nr+1)][4]byte) []byte {
+// decryptBlock decifra src e escreve o resultado em dst (pode ser in-place).
+func decryptBlock(dst, src []byte, roundKeys []byte, hook roundHook) {
 	var state State
+	rows := state.rows()
+	loadState(rows, src)
+	decryptRounds(rows, roundKeys, &invSbox, hook)
+	storeState(dst, rows)
+}
 
-	for i := 0; i < 16; i++ {
-		state[i%4][i/4] = input[i]
+// decryptRounds desfaz encryptRounds; inv é a inversa da S-box usada na cifragem.
+func decryptRounds(rows [4][]byte, roundKeys []byte, inv *[256]byte, hook roundHook) {
+	n := 4 * len(rows[0])
+	nr := len(roundKeys)/n - 1
+	view := hookView(hook, len(rows[0]))
+	step := func(round int, name string, roundKey []byte) {
+		callHook(hook, view, rows, round, name, roundKey)
 	}
+	step(nr, StepInput, nil)
 
 	// Rodada inicial
-	AddRoundKey(&state, flattenKey(expandedKey[Nr*4:(Nr+1)*4]))
-
-	for round := Nr - 1; round >= 1; round-- {
-		InvShiftRows(&state)
-		InvSubBytes(&state)
-		AddRoundKey(&state, flattenKey(expandedKey[round*4:(round+1)*4]))
-		InvMixColumns(&state)
+	roundKey := roundKeys[nr*n : (nr+1)*n]
+	addRoundKey(rows, roundKey)
+	step(nr, StepAddRoundKey, roundKey)
+
+	for round := nr - 1; round >= 1; round-- {
+		step(round, StepStart, nil)
+		shiftRows(rows, true)
+		step(round, StepInvShiftRows, nil)
+		subBytes(rows, inv)
+		step(round, StepInvSubBytes, nil)
+		roundKey = roundKeys[round*n : (round+1)*n]
+		addRoundKey(rows, roundKey)
+		step(round, StepAddRoundKey, roundKey)
+		invMixColumns(rows)
+		step(round, StepInvMixColumns, nil)
 	}
 
 	// Rodada final
-	InvShiftRows(&state)
-	InvSubBytes(&state)
-	AddRoundKey(&state, flattenKey(expandedKey[0:4]))
-
-	// Retorno
-	output := make([]byte, 16)
-	for i := 0; i < 16; i++ {
-		output[i] = state[i%4][i/4]
-	}
-	return output
+	step(0, StepStart, nil)
+	shiftRows(rows, true)
+	step(0, StepInvShiftRows, nil)
+	subBytes(rows, inv)
+	step(0, StepInvSubBytes, nil)
+	roundKey = roundKeys[0:n]
+	addRoundKey(rows, roundKey)
+	step(0, StepAddRoundKey, roundKey)
+	step(0, StepOutput, nil)
 }
 
 func main() {
@@ -383,10 +676,14 @@ func main() {
 		0xe0, 0x37, 0x07, 0x34,
 	}
 
-	expandedKey := KeyExpansion(key)
-	ciphertext := EncryptBlock(plaintext, expandedKey)
+	a, err := New(key)
+	if err != nil {
+		panic(err)
+	}
+
+	ciphertext := a.EncryptBlock(plaintext)
 	fmt.Printf("Cifrado:   %x\n", ciphertext)
 
-	decrypted := DecryptBlock(ciphertext, expandedKey)
+	decrypted := a.DecryptBlock(ciphertext)
 	fmt.Printf("Decifrado: %x\n", decrypted)
 }
@@ -0,0 +1,457 @@
+/*
+
+AES bitsliced (tempo constante)
+
+SubBytes com a tabela sbox (ou as T-tables de aes_ttable.go) faz acessos à
+memória cujo endereço depende do byte secreto. O tempo desses acessos varia
+com o que está no cache, e isso basta para recuperar a chave (Bernstein 2005,
+Osvik-Shamir-Tromer 2006).
+
+A técnica de bitslicing elimina as tabelas: em vez de guardar bytes, cada
+palavra de 64 bits guarda UM bit de 64 bytes diferentes.
+
+	q[b] bit (row*16 + col*4 + blk) = bit b do byte (row, col) do bloco blk
+
+Com 4 blocos de 16 bytes = 64 bytes, os 8 bits de todos eles cabem em
+q[0..7]. Todas as etapas viram operações booleanas sobre as 8 palavras:
+
+	SubBytes    -> circuito de portas XOR/AND/NOT (sboxCircuit)
+	ShiftRows   -> shifts e máscaras dentro de cada linha (16 bits por linha)
+	MixColumns  -> rotações de 16 bits (troca de linha) + xtime "bit a bit"
+	AddRoundKey -> XOR com a chave também em formato bitsliced
+
+Nenhum índice de memória e nenhum desvio dependem dos dados ou da chave,
+inclusive na expansão da chave (ctKeyExpansion).
+
+*/
+
+package main
+
+import (
+	"crypto/cipher"
+	"math/bits"
+)
+
+// bitslicedBlocks é o número de blocos processados em paralelo.
+const bitslicedBlocks = 4
+
+// Bitsliced é uma instância do AES em que todas as rodadas usam apenas
+// operações booleanas sobre palavras de 64 bits.
+type Bitsliced struct {
+	nr        int
+	roundKeys [][8]uint64 // uma chave bitsliced (replicada nos 4 blocos) por rodada
+}
+
+// NewBitsliced cria a versão bitsliced a partir de uma chave de 16, 24 ou 32 bytes.
+func NewBitsliced(key []byte) (*Bitsliced, error) {
+	switch len(key) {
+	case 16, 24, 32:
+	default:
+		return nil, KeySizeError(len(key))
+	}
+
+	w := ctKeyExpansion(key)
+	nr := numRounds(w)
+
+	b := &Bitsliced{nr: nr, roundKeys: make([][8]uint64, nr+1)}
+	for round := 0; round <= nr; round++ {
+		var blocks [bitslicedBlocks * BlockSize]byte
+		for blk := 0; blk < bitslicedBlocks; blk++ {
+			for c := 0; c < Nb; c++ {
+				copy(blocks[blk*BlockSize+4*c:], w[round*Nb+c][:])
+			}
+		}
+		b.roundKeys[round] = bitslice(blocks[:])
+	}
+	return b, nil
+}
+
+func (b *Bitsliced) BlockSize() int {
+	return BlockSize
+}
+
+// Encrypt cifra um único bloco (os outros 3 "espaços" do lote ficam vazios).
+func (b *Bitsliced) Encrypt(dst, src []byte) {
+	checkBlocks(dst, src)
+	var buf [bitslicedBlocks * BlockSize]byte
+	copy(buf[:], src[:BlockSize])
+	b.EncryptBlocks(buf[:], buf[:])
+	copy(dst, buf[:BlockSize])
+}
+
+// Decrypt decifra um único bloco.
+func (b *Bitsliced) Decrypt(dst, src []byte) {
+	checkBlocks(dst, src)
+	var buf [bitslicedBlocks * BlockSize]byte
+	copy(buf[:], src[:BlockSize])
+	b.DecryptBlocks(buf[:], buf[:])
+	copy(dst, buf[:BlockSize])
+}
+
+/*
+EncryptBlocks: Cifra vários blocos independentes (como no ECB), processando
+4 por vez. len(src) deve ser múltiplo de 16; o último lote pode ser parcial.
+*/
+func (b *Bitsliced) EncryptBlocks(dst, src []byte) {
+	b.cryptBlocks(dst, src, false)
+}
+
+// DecryptBlocks decifra vários blocos independentes, 4 por vez.
+func (b *Bitsliced) DecryptBlocks(dst, src []byte) {
+	b.cryptBlocks(dst, src, true)
+}
+
+func (b *Bitsliced) cryptBlocks(dst, src []byte, decrypt bool) {
+	if len(src)%BlockSize != 0 {
+		panic("aes: entrada não é múltipla do bloco")
+	}
+	if len(dst) < len(src) {
+		panic("aes: saída menor que a entrada")
+	}
+
+	const batchSize = bitslicedBlocks * BlockSize
+	for i := 0; i < len(src); i += batchSize {
+		var buf [batchSize]byte
+		n := copy(buf[:], src[i:])
+		q := bitslice(buf[:])
+		if decrypt {
+			b.decryptBatch(&q)
+		} else {
+			b.encryptBatch(&q)
+		}
+		unbitslice(&q, buf[:])
+		copy(dst[i:i+n], buf[:n])
+	}
+}
+
+func (b *Bitsliced) encryptBatch(q *[8]uint64) {
+	addRoundKeyBitsliced(q, &b.roundKeys[0])
+	for round := 1; round < b.nr; round++ {
+		sboxCircuit(q)
+		shiftRowsBitsliced(q)
+		mixColumnsBitsliced(q)
+		addRoundKeyBitsliced(q, &b.roundKeys[round])
+	}
+	sboxCircuit(q)
+	shiftRowsBitsliced(q)
+	addRoundKeyBitsliced(q, &b.roundKeys[b.nr])
+}
+
+func (b *Bitsliced) decryptBatch(q *[8]uint64) {
+	addRoundKeyBitsliced(q, &b.roundKeys[b.nr])
+	for round := b.nr - 1; round >= 1; round-- {
+		invShiftRowsBitsliced(q)
+		invSboxCircuit(q)
+		addRoundKeyBitsliced(q, &b.roundKeys[round])
+		invMixColumnsBitsliced(q)
+	}
+	invShiftRowsBitsliced(q)
+	invSboxCircuit(q)
+	addRoundKeyBitsliced(q, &b.roundKeys[0])
+}
+
+// bitsliceIndex devolve a posição (0..63) do byte i de um lote de 4 blocos.
+func bitsliceIndex(i int) int {
+	blk, pos := i/BlockSize, i%BlockSize
+	row, col := pos%4, pos/4
+	return row*16 + col*4 + blk
+}
+
+// bitslice transpõe 64 bytes em 8 palavras: q[b] contém o bit b de cada byte.
+func bitslice(in []byte) [8]uint64 {
+	var q [8]uint64
+	for i := 0; i < bitslicedBlocks*BlockSize; i++ {
+		idx := bitsliceIndex(i)
+		for b := 0; b < 8; b++ {
+			q[b] |= uint64(in[i]>>b&1) << idx
+		}
+	}
+	return q
+}
+
+// unbitslice desfaz bitslice.
+func unbitslice(q *[8]uint64, out []byte) {
+	for i := 0; i < bitslicedBlocks*BlockSize; i++ {
+		idx := bitsliceIndex(i)
+		var v byte
+		for b := 0; b < 8; b++ {
+			v |= byte(q[b]>>idx&1) << b
+		}
+		out[i] = v
+	}
+}
+
+func addRoundKeyBitsliced(q, rk *[8]uint64) {
+	for b := 0; b < 8; b++ {
+		q[b] ^= rk[b]
+	}
+}
+
+/*
+shiftRowsBitsliced: Cada linha ocupa 16 bits (4 colunas x 4 blocos). Deslocar
+a linha r de r colunas para a esquerda é rotacionar esses 16 bits de 4*r.
+*/
+func shiftRowsBitsliced(q *[8]uint64) {
+	for b := 0; b < 8; b++ {
+		x := q[b]
+		q[b] = x&0x000000000000ffff |
+			(x&0x00000000fff00000)>>4 | (x&0x00000000000f0000)<<12 |
+			(x&0x0000ff0000000000)>>8 | (x&0x000000ff00000000)<<8 |
+			(x&0xf000000000000000)>>12 | (x&0x0fff000000000000)<<4
+	}
+}
+
+func invShiftRowsBitsliced(q *[8]uint64) {
+	for b := 0; b < 8; b++ {
+		x := q[b]
+		q[b] = x&0x000000000000ffff |
+			(x&0x000000000fff0000)<<4 | (x&0x00000000f0000000)>>12 |
+			(x&0x0000ff0000000000)>>8 | (x&0x000000ff00000000)<<8 |
+			(x&0x000f000000000000)<<12 | (x&0xfff0000000000000)>>4
+	}
+}
+
+// xtimeBitsliced multiplica por x os 64 bytes de uma vez (redução por 0x1b).
+func xtimeBitsliced(t [8]uint64) [8]uint64 {
+	return [8]uint64{t[7], t[0] ^ t[7], t[1], t[2] ^ t[7], t[3] ^ t[7], t[4], t[5], t[6]}
+}
+
+// rotRows troca a linha r pela linha r+n de cada coluna (rotação de 16*n bits).
+func rotRows(q [8]uint64, n int) [8]uint64 {
+	for b := 0; b < 8; b++ {
+		q[b] = bits.RotateLeft64(q[b], -16*n)
+	}
+	return q
+}
+
+/*
+mixColumnsBitsliced: Para cada linha r de cada coluna:
+
+	out_r = 02·a_r ^ 03·a_(r+1) ^ a_(r+2) ^ a_(r+3)
+	      = xtime(a_r ^ a_(r+1)) ^ a_(r+1) ^ a_(r+2) ^ a_(r+3)
+*/
+func mixColumnsBitsliced(q *[8]uint64) {
+	a1 := rotRows(*q, 1)
+	a2 := rotRows(*q, 2)
+	a3 := rotRows(*q, 3)
+
+	var t [8]uint64
+	for b := 0; b < 8; b++ {
+		t[b] = q[b] ^ a1[b]
+	}
+	t = xtimeBitsliced(t)
+	for b := 0; b < 8; b++ {
+		q[b] = t[b] ^ a1[b] ^ a2[b] ^ a3[b]
+	}
+}
+
+/*
+invMixColumnsBitsliced: Usa a decomposição do livro "The Design of Rijndael"
+(seção 4.1.3): InvMixColumns = MixColumns após um pré-processamento
+
+	u = 04·(a_0 ^ a_2), v = 04·(a_1 ^ a_3)
+	a_0 ^= u, a_1 ^= v, a_2 ^= u, a_3 ^= v
+*/
+func invMixColumnsBitsliced(q *[8]uint64) {
+	a2 := rotRows(*q, 2)
+
+	var t [8]uint64
+	for b := 0; b < 8; b++ {
+		t[b] = q[b] ^ a2[b]
+	}
+	t = xtimeBitsliced(xtimeBitsliced(t))
+	for b := 0; b < 8; b++ {
+		q[b] ^= t[b]
+	}
+	mixColumnsBitsliced(q)
+}
+
+/*
+sboxCircuit: S-box do AES como circuito booleano (Boyar e Peralta, "A depth-16
+circuit for the AES S-box", 2011): 113 portas (32 AND, 77 XOR e 4 XNOR).
+
+O circuito calcula exatamente sboxByte de aes_sbox.go,
+S(x) = affineTransform(gfInv(x)), mas reorganizado em três camadas:
+
+ 1. Camada linear de entrada: muda a base de GF(2^8) para a torre
+    GF(((2^2)^2)^2), onde a inversão é muito mais barata.
+ 2. Camada não linear: a inversão (gfInv) na torre, com as únicas 32
+    portas AND do circuito.
+ 3. Camada linear de saída: volta para a base polinomial do AES e aplica
+    a matriz de affineTransform. A constante 0x63 = 01100011 aparece como
+    as 4 portas XNOR (^ com NOT) nos bits de saída 6, 5, 1 e 0.
+
+Convenção: x0 é o bit mais significativo (q[7]) e x7 o menos (q[0]).
+*/
+func sboxCircuit(q *[8]uint64) {
+	x0, x1, x2, x3 := q[7], q[6], q[5], q[4]
+	x4, x5, x6, x7 := q[3], q[2], q[1], q[0]
+
+	// Camada linear de entrada
+	y14 := x3 ^ x5
+	y13 := x0 ^ x6
+	y9 := x0 ^ x3
+	y8 := x0 ^ x5
+	t0 := x1 ^ x2
+	y1 := t0 ^ x7
+	y4 := y1 ^ x3
+	y12 := y13 ^ y14
+	y2 := y1 ^ x0
+	y5 := y1 ^ x6
+	y3 := y5 ^ y8
+	t1 := x4 ^ y12
+	y15 := t1 ^ x5
+	y20 := t1 ^ x1
+	y6 := y15 ^ x7
+	y10 := y15 ^ t0
+	y11 := y20 ^ y9
+	y7 := x7 ^ y11
+	y17 := y10 ^ y11
+	y19 := y10 ^ y8
+	y16 := t0 ^ y11
+	y21 := y13 ^ y16
+	y18 := x0 ^ y16
+
+	// Camada não linear (inversão na torre de corpos)
+	t2 := y12 & y15
+	t3 := y3 & y6
+	t4 := t3 ^ t2
+	t5 := y4 & x7
+	t6 := t5 ^ t2
+	t7 := y13 & y16
+	t8 := y5 & y1
+	t9 := t8 ^ t7
+	t10 := y2 & y7
+	t11 := t10 ^ t7
+	t12 := y9 & y11
+	t13 := y14 & y17
+	t14 := t13 ^ t12
+	t15 := y8 & y10
+	t16 := t15 ^ t12
+	t17 := t4 ^ t14
+	t18 := t6 ^ t16
+	t19 := t9 ^ t14
+	t20 := t11 ^ t16
+	t21 := t17 ^ y20
+	t22 := t18 ^ y19
+	t23 := t19 ^ y21
+	t24 := t20 ^ y18
+
+	t25 := t21 ^ t22
+	t26 := t21 & t23
+	t27 := t24 ^ t26
+	t28 := t25 & t27
+	t29 := t28 ^ t22
+	t30 := t23 ^ t24
+	t31 := t22 ^ t26
+	t32 := t31 & t30
+	t33 := t32 ^ t24
+	t34 := t23 ^ t33
+	t35 := t27 ^ t33
+	t36 := t24 & t35
+	t37 := t36 ^ t34
+	t38 := t27 ^ t36
+	t39 := t29 & t38
+	t40 := t25 ^ t39
+
+	t41 := t40 ^ t37
+	t42 := t29 ^ t33
+	t43 := t29 ^ t40
+	t44 := t33 ^ t37
+	t45 := t42 ^ t41
+	z0 := t44 & y15
+	z1 := t37 & y6
+	z2 := t33 & x7
+	z3 := t43 & y16
+	z4 := t40 & y1
+	z5 := t29 & y7
+	z6 := t42 & y11
+	z7 := t45 & y17
+	z8 := t41 & y10
+	z9 := t44 & y12
+	z10 := t37 & y3
+	z11 := t33 & y4
+	z12 := t43 & y13
+	z13 := t40 & y5
+	z14 := t29 & y2
+	z15 := t42 & y9
+	z16 := t45 & y14
+	z17 := t41 & y8
+
+	// Camada linear de saída (inclui a transformação afim)
+	t46 := z15 ^ z16
+	t47 := z10 ^ z11
+	t48 := z5 ^ z13
+	t49 := z9 ^ z10
+	t50 := z2 ^ z12
+	t51 := z2 ^ z5
+	t52 := z7 ^ z8
+	t53 := z0 ^ z3
+	t54 := z6 ^ z7
+	t55 := z16 ^ z17
+	t56 := z12 ^ t48
+	t57 := t50 ^ t53
+	t58 := z4 ^ t46
+	t59 := z3 ^ t54
+	t60 := t46 ^ t57
+	t61 := z14 ^ t57
+	t62 := t52 ^ t58
+	t63 := t49 ^ t58
+	t64 := z4 ^ t59
+	t65 := t61 ^ t62
+	t66 := z1 ^ t63
+	s0 := t59 ^ t63
+	s6 := t56 ^ ^t62
+	s7 := t48 ^ ^t60
+	t67 := t64 ^ t65
+	s3 := t53 ^ t66
+	s4 := t51 ^ t66
+	s5 := t47 ^ t65
+	s1 := t64 ^ ^s3
+	s2 := t55 ^ ^t67
+
+	q[7], q[6], q[5], q[4] = s0, s1, s2, s3
+	q[3], q[2], q[1], q[0] = s4, s5, s6, s7
+}
+
+/*
+invSboxCircuit: A inversa reaproveita o mesmo circuito. Como
+S(x) = A(x^-1) ^ 0x63, vale
+
+	S^-1(y) = L(S(L(y))),  com L(y) = inverseAffineTransform(y)
+
+ou seja, basta aplicar a transformação afim inversa (linear + constante
+0x05) antes e depois de sboxCircuit.
+*/
+func invSboxCircuit(q *[8]uint64) {
+	inverseAffineBitsliced(q)
+	sboxCircuit(q)
+	inverseAffineBitsliced(q)
+}
+
+// inverseAffineBitsliced: b_i = y_(i+2) ^ y_(i+5) ^ y_(i+7) ^ d_i, com d = 0x05.
+func inverseAffineBitsliced(q *[8]uint64) {
+	y := *q
+	for i := 0; i < 8; i++ {
+		q[i] = y[(i+2)%8] ^ y[(i+5)%8] ^ y[(i+7)%8]
+	}
+	q[0] = ^q[0]
+	q[2] = ^q[2]
+}
+
+// subWordCT é o SubWord do key schedule calculado pelo circuito, sem tabela.
+func subWordCT(word [4]byte) [4]byte {
+	var in [bitslicedBlocks * BlockSize]byte
+	copy(in[:], word[:])
+	q := bitslice(in[:])
+	sboxCircuit(&q)
+	unbitslice(&q, in[:])
+	return [4]byte{in[0], in[1], in[2], in[3]}
+}
+
+// ctKeyExpansion é o KeyExpansion de aes.go com SubWord em tempo constante.
+func ctKeyExpansion(key []byte) [][4]byte {
+	return expandKey(key, Nb, subWordCT)
+}
+
+var _ cipher.Block = (*Bitsliced)(nil)
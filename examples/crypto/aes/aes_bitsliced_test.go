@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bytes"
+	"crypto/aes"
+	"math/rand"
+	"testing"
+)
+
+// O circuito deve reproduzir affineTransform(gfInv(x)) de aes_sbox.go para
+// todas as 256 entradas (e a inversa, invSboxByte).
+func TestSboxCircuitDerivadoDeGfInv(t *testing.T) {
+	var in [bitslicedBlocks * BlockSize]byte
+	for base := 0; base < 256; base += len(in) {
+		for i := range in {
+			in[i] = byte(base + i)
+		}
+
+		q := bitslice(in[:])
+		sboxCircuit(&q)
+		var out [len(in)]byte
+		unbitslice(&q, out[:])
+
+		q = bitslice(in[:])
+		invSboxCircuit(&q)
+		var inv [len(in)]byte
+		unbitslice(&q, inv[:])
+
+		for i := range in {
+			if want := sboxByte(in[i]); out[i] != want {
+				t.Fatalf("sboxCircuit(%02x) = %02x, esperado %02x", in[i], out[i], want)
+			}
+			if want := invSboxByte(in[i]); inv[i] != want {
+				t.Fatalf("invSboxCircuit(%02x) = %02x, esperado %02x", in[i], inv[i], want)
+			}
+		}
+	}
+}
+
+func TestBitslicedTransposicao(t *testing.T) {
+	in := make([]byte, bitslicedBlocks*BlockSize)
+	rand.New(rand.NewSource(1)).Read(in)
+
+	q := bitslice(in)
+	out := make([]byte, len(in))
+	unbitslice(&q, out)
+	if !bytes.Equal(in, out) {
+		t.Fatalf("unbitslice(bitslice(x)) != x")
+	}
+
+	// ShiftRows e MixColumns bitsliced devem bater com as versões de aes.go
+	for blk := 0; blk < bitslicedBlocks; blk++ {
+		var s State
+		for i := 0; i < 16; i++ {
+			s[i%4][i/4] = in[blk*BlockSize+i]
+		}
+		ShiftRows(&s)
+		MixColumns(&s)
+
+		q := bitslice(in)
+		shiftRowsBitsliced(&q)
+		mixColumnsBitsliced(&q)
+		unbitslice(&q, out)
+		if got, want := out[blk*BlockSize:(blk+1)*BlockSize], s.Bytes(); !bytes.Equal(got, want) {
+			t.Fatalf("bloco %d: ShiftRows+MixColumns bitsliced %x, esperado %x", blk, got, want)
+		}
+
+		invMixColumnsBitsliced(&q)
+		invShiftRowsBitsliced(&q)
+		unbitslice(&q, out)
+		if !bytes.Equal(out, in) {
+			t.Fatalf("inversas bitsliced não desfazem a rodada")
+		}
+	}
+}
+
+func TestCtKeyExpansion(t *testing.T) {
+	for _, n := range []int{16, 24, 32} {
+		key := make([]byte, n)
+		rand.New(rand.NewSource(int64(n))).Read(key)
+		ref := KeyExpansion(key)
+		ct := ctKeyExpansion(key)
+		for i := range ref {
+			if ref[i] != ct[i] {
+				t.Fatalf("chave de %d bytes, palavra %d: %x != %x", n, i, ct[i], ref[i])
+			}
+		}
+	}
+}
+
+// Mesmos vetores do FIPS-197 usados para EncryptBlock (aes_test.go).
+func TestBitslicedApendiceC(t *testing.T) {
+	plaintext := mustHex(t, "00112233445566778899aabbccddeeff")
+	casos := []struct{ key, ciphertext string }{
+		{"000102030405060708090a0b0c0d0e0f", "69c4e0d86a7b0430d8cdb78070b4c55a"},
+		{"000102030405060708090a0b0c0d0e0f1011121314151617", "dda97ca4864cdfe06eaf70a0ec0d7191"},
+		{"000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f", "8ea2b7ca516745bfeafc49904b496089"},
+	}
+
+	for _, c := range casos {
+		b, err := NewBitsliced(mustHex(t, c.key))
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := mustHex(t, c.ciphertext)
+
+		got := make([]byte, BlockSize)
+		b.Encrypt(got, plaintext)
+		if !bytes.Equal(got, expected) {
+			t.Errorf("chave %s: obtido %x, esperado %x", c.key, got, expected)
+		}
+		b.Decrypt(got, got)
+		if !bytes.Equal(got, plaintext) {
+			t.Errorf("chave %s: decifrado %x", c.key, got)
+		}
+	}
+
+	key := []byte{
+		0x2b, 0x7e, 0x15, 0x16, 0x28, 0xae, 0xd2, 0xa6,
+		0xab, 0xf7, 0x15, 0x88, 0x09, 0xcf, 0x4f, 0x3c,
+	}
+	b, _ := NewBitsliced(key)
+	got := make([]byte, BlockSize)
+	b.Encrypt(got, mustHex(t, "3243f6a8885a308d313198a2e0370734"))
+	if want := mustHex(t, "3925841d02dc09fbdc118597196a0b32"); !bytes.Equal(got, want) {
+		t.Errorf("apêndice B: obtido %x, esperado %x", got, want)
+	}
+}
+
+// Vários blocos por chamada (inclusive lote final parcial) contra crypto/aes.
+func TestBitslicedVariosBlocos(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for _, keySize := range []int{16, 24, 32} {
+		key := make([]byte, keySize)
+		rng.Read(key)
+		b, _ := NewBitsliced(key)
+		ref, _ := aes.NewCipher(key)
+
+		for nblocks := 0; nblocks <= 9; nblocks++ {
+			src := make([]byte, nblocks*BlockSize)
+			rng.Read(src)
+
+			want := make([]byte, len(src))
+			for i := 0; i < len(src); i += BlockSize {
+				ref.Encrypt(want[i:], src[i:])
+			}
+
+			got := make([]byte, len(src))
+			b.EncryptBlocks(got, src)
+			if !bytes.Equal(got, want) {
+				t.Fatalf("chave=%d blocos=%d: obtido %x, esperado %x", keySize, nblocks, got, want)
+			}
+
+			b.DecryptBlocks(got, got)
+			if !bytes.Equal(got, src) {
+				t.Fatalf("chave=%d blocos=%d: decifragem falhou", keySize, nblocks)
+			}
+		}
+	}
+}
+
+func BenchmarkEncryptBitsliced(b *testing.B) {
+	bs, _ := NewBitsliced(make([]byte, 16))
+	benchmarkEncrypt(b, bs.Encrypt)
+}
+
+func BenchmarkEncryptBitsliced4Blocos(b *testing.B) {
+	bs, _ := NewBitsliced(make([]byte, 16))
+	buf := make([]byte, bitslicedBlocks*BlockSize)
+	b.SetBytes(int64(len(buf)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		bs.EncryptBlocks(buf, buf)
+	}
+}
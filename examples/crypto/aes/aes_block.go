@@ -0,0 +1,57 @@
+package main
+
+import (
+	"crypto/cipher"
+)
+
+// BlockSize é o tamanho do bloco do AES em bytes (Nb palavras de 4 bytes).
+const BlockSize = Nb * 4
+
+/*
+NewCipher: Cria um cipher.Block a partir de uma chave de 16, 24 ou 32 bytes.
+
+	É o equivalente didático de crypto/aes.NewCipher: o valor retornado pode
+	ser usado em qualquer código que receba um cipher.Block (modos de operação,
+	cipher.NewCBCEncrypter, cipher.NewGCM etc.).
+
+	Chaves com tamanho inválido retornam KeySizeError em vez de causar um
+	panic de índice dentro de KeyExpansion.
+*/
+func NewCipher(key []byte) (cipher.Block, error) {
+	return New(key)
+}
+
+// BlockSize retorna o tamanho do bloco (16 bytes), exigido por cipher.Block.
+func (a *AES) BlockSize() int {
+	return BlockSize
+}
+
+/*
+Encrypt: Cifra o primeiro bloco de src e grava o resultado em dst.
+
+	Diferente de EncryptBlock, não aloca um slice novo por chamada e aceita
+	dst e src sobrepostos exatamente (cifragem in-place).
+
+	A interface cipher.Block não permite retornar erro, então, assim como
+	crypto/aes, blocos curtos geram um panic com mensagem explícita em vez
+	de um "index out of range" no meio das rodadas.
+*/
+func (a *AES) Encrypt(dst, src []byte) {
+	checkBlocks(dst, src)
+	encryptBlock(dst, src, a.roundKeys, nil)
+}
+
+// Decrypt decifra o primeiro bloco de src e grava o resultado em dst.
+func (a *AES) Decrypt(dst, src []byte) {
+	checkBlocks(dst, src)
+	decryptBlock(dst, src, a.roundKeys, nil)
+}
+
+func checkBlocks(dst, src []byte) {
+	if len(src) < BlockSize {
+		panic("aes: bloco de entrada incompleto")
+	}
+	if len(dst) < BlockSize {
+		panic("aes: bloco de saída incompleto")
+	}
+}
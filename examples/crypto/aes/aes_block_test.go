@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+var _ cipher.Block = (*AES)(nil)
+
+// Teste diferencial: compara NewCipher com crypto/aes em chaves e blocos aleatórios.
+func TestNewCipherDiferencial(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for _, keySize := range []int{16, 24, 32} {
+		for i := 0; i < 200; i++ {
+			key := make([]byte, keySize)
+			src := make([]byte, BlockSize)
+			rng.Read(key)
+			rng.Read(src)
+
+			nosso, err := NewCipher(key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ref, err := aes.NewCipher(key)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := make([]byte, BlockSize)
+			want := make([]byte, BlockSize)
+			nosso.Encrypt(got, src)
+			ref.Encrypt(want, src)
+			if !bytes.Equal(got, want) {
+				t.Fatalf("Encrypt chave=%x bloco=%x: obtido %x, esperado %x", key, src, got, want)
+			}
+
+			nosso.Decrypt(got, src)
+			ref.Decrypt(want, src)
+			if !bytes.Equal(got, want) {
+				t.Fatalf("Decrypt chave=%x bloco=%x: obtido %x, esperado %x", key, src, got, want)
+			}
+		}
+	}
+}
+
+func TestNewCipherInPlace(t *testing.T) {
+	key := []byte{
+		0x2b, 0x7e, 0x15, 0x16, 0x28, 0xae, 0xd2, 0xa6,
+		0xab, 0xf7, 0x15, 0x88, 0x09, 0xcf, 0x4f, 0x3c,
+	}
+	plaintext := []byte{
+		0x32, 0x43, 0xf6, 0xa8, 0x88, 0x5a, 0x30, 0x8d,
+		0x31, 0x31, 0x98, 0xa2, 0xe0, 0x37, 0x07, 0x34,
+	}
+
+	block, err := NewCipher(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := append([]byte(nil), plaintext...)
+	block.Encrypt(buf, buf)
+	if want := EncryptBlock(plaintext, KeyExpansion(key)); !bytes.Equal(buf, want) {
+		t.Fatalf("Encrypt in-place: obtido %x, esperado %x", buf, want)
+	}
+	block.Decrypt(buf, buf)
+	if !bytes.Equal(buf, plaintext) {
+		t.Fatalf("Decrypt in-place: obtido %x, esperado %x", buf, plaintext)
+	}
+}
+
+func TestNewCipherErros(t *testing.T) {
+	_, err := NewCipher(make([]byte, 20))
+	var kse KeySizeError
+	if !errors.As(err, &kse) || int(kse) != 20 {
+		t.Fatalf("esperado KeySizeError(20), obtido %v", err)
+	}
+
+	block, err := NewCipher(make([]byte, 16))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertPanic := func(nome string, fn func()) {
+		t.Helper()
+		defer func() {
+			if recover() == nil {
+				t.Errorf("%s: esperado panic com bloco curto", nome)
+			}
+		}()
+		fn()
+	}
+	assertPanic("Encrypt src curto", func() { block.Encrypt(make([]byte, 16), make([]byte, 15)) })
+	assertPanic("Encrypt dst curto", func() { block.Encrypt(make([]byte, 15), make([]byte, 16)) })
+	assertPanic("Decrypt src curto", func() { block.Decrypt(make([]byte, 16), make([]byte, 15)) })
+}
+
+// As chaves de rodada são achatadas uma vez em New: Encrypt e Decrypt não alocam.
+func TestNewCipherSemAlocacao(t *testing.T) {
+	for _, keySize := range []int{16, 24, 32} {
+		block, _ := NewCipher(make([]byte, keySize))
+		buf := make([]byte, BlockSize)
+		if n := testing.AllocsPerRun(100, func() { block.Encrypt(buf, buf) }); n != 0 {
+			t.Errorf("AES-%d: Encrypt alocou %.0f vezes por bloco", 8*keySize, n)
+		}
+		if n := testing.AllocsPerRun(100, func() { block.Decrypt(buf, buf) }); n != 0 {
+			t.Errorf("AES-%d: Decrypt alocou %.0f vezes por bloco", 8*keySize, n)
+		}
+	}
+}
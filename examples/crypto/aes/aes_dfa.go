@@ -0,0 +1,293 @@
+/*
+
+Análise diferencial de falhas (DFA) contra o AES
+
+Um atacante com acesso físico ao dispositivo (glitch de clock/tensão, laser)
+consegue corromper um byte do state no meio da cifragem. Comparando o
+ciphertext correto com o defeituoso, para o MESMO plaintext, dá para deduzir
+a última chave de rodada.
+
+Ataque de Piret e Quisquater (CHES 2003), falha antes do MixColumns da
+rodada Nr-1 (rodada 9 no AES-128):
+
+	rodada 9:  falha e no byte (r, c) -> MixColumns espalha para a coluna c:
+	           diferença (M[0][r]·e, M[1][r]·e, M[2][r]·e, M[3][r]·e)
+	           AddRoundKey não muda diferenças
+	rodada 10: SubBytes, ShiftRows (a linha i vai para a coluna c-i),
+	           AddRoundKey com K10 -> 4 bytes do ciphertext diferentes
+
+Para cada um desses 4 bytes vale
+
+	InvSbox(C[p_i] ^ K10[p_i]) ^ InvSbox(C'[p_i] ^ K10[p_i]) = M[i][r]·e
+
+Percorrendo as 255 possíveis falhas e (e as 4 linhas r, se a posição for
+desconhecida) sobram cerca de 2^10 candidatos para os 4 bytes de K10
+daquela coluna. Um segundo par na mesma coluna normalmente deixa um único
+candidato. Com as 4 colunas resolvidas, RecoverKey volta até a chave.
+
+*/
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+)
+
+// FaultModel descreve o que o atacante controla/sabe sobre a falha.
+type FaultModel int
+
+const (
+	FaultRandomByte    FaultModel = iota // byte e posição aleatórios e desconhecidos
+	FaultKnownPosition                   // posição escolhida, valor aleatório desconhecido
+	FaultSingleBit                       // posição escolhida, apenas um bit invertido
+)
+
+func (m FaultModel) String() string {
+	switch m {
+	case FaultRandomByte:
+		return "byte aleatório"
+	case FaultKnownPosition:
+		return "posição conhecida"
+	case FaultSingleBit:
+		return "bit único"
+	default:
+		return fmt.Sprintf("FaultModel(%d)", int(m))
+	}
+}
+
+// Fault é uma falha concreta: XOR de Mask no byte Position (ordem de entrada,
+// coluna a coluna) do state, logo antes do MixColumns da rodada Round.
+type Fault struct {
+	Round    int
+	Position int
+	Mask     byte
+}
+
+/*
+EncryptBlockWithFault: Igual a EncryptBlock, mas injeta a falha no state
+antes do MixColumns da rodada fault.Round (1..Nr-1), pelo mesmo hook que o
+tracer usa.
+*/
+func EncryptBlockWithFault(input []byte, expandedKey [][4]byte, fault Fault) []byte {
+	output := make([]byte, BlockSize)
+	encryptBlock(output, input, flattenKey(expandedKey), func(round int, step string, rows [4][]byte, _ []byte) {
+		if round == fault.Round && step == StepShiftRows {
+			rows[fault.Position%4][fault.Position/4] ^= fault.Mask
+		}
+	})
+	return output
+}
+
+// FaultyPair é o que o atacante observa: o ciphertext correto e o defeituoso
+// do mesmo plaintext, e a posição da falha quando o modelo a revela (-1 se não).
+type FaultyPair struct {
+	Correct  []byte
+	Faulty   []byte
+	Model    FaultModel
+	Position int
+}
+
+// FaultSimulator simula o dispositivo atacado: guarda a chave e injeta
+// falhas na rodada Nr-1 conforme o modelo escolhido.
+type FaultSimulator struct {
+	cipher *AES
+	model  FaultModel
+	rng    *rand.Rand
+}
+
+/*
+NewFaultSimulator: Cria o dispositivo com uma chave de 16 bytes.
+
+	O ataque só está implementado para o AES-128: com 192 ou 256 bits a
+	última chave de rodada não basta para voltar até a chave (RecoverKey
+	precisa de Nk palavras consecutivas), então outras chaves são recusadas
+	em vez de produzir uma chave errada em DFARecoverKey.
+*/
+func NewFaultSimulator(key []byte, model FaultModel, seed int64) (*FaultSimulator, error) {
+	if len(key) != 16 {
+		return nil, fmt.Errorf("aes-dfa: o ataque exige AES-128, chave de %d bytes", len(key))
+	}
+	a, err := New(key)
+	if err != nil {
+		return nil, err
+	}
+	return &FaultSimulator{cipher: a, model: model, rng: rand.New(rand.NewSource(seed))}, nil
+}
+
+/*
+Inject: Cifra plaintext duas vezes, uma delas com falha.
+
+	position é a posição escolhida pelo atacante (0..15) nos modelos
+	FaultKnownPosition e FaultSingleBit; em FaultRandomByte é ignorada e
+	sorteada pelo simulador. O valor da falha é sempre sorteado.
+
+	Inject não retorna erro, então, como checkBlocks, uma posição fora de
+	0..15 nesses modelos gera um panic com mensagem explícita em vez de um
+	"index out of range" no meio das rodadas.
+*/
+func (s *FaultSimulator) Inject(plaintext []byte, position int) FaultyPair {
+	if s.model != FaultRandomByte && (position < 0 || position >= BlockSize) {
+		panic(fmt.Sprintf("aes-dfa: posição de falha inválida: %d (use 0 a 15)", position))
+	}
+	fault := Fault{Round: s.cipher.Nr - 1, Position: position}
+	pair := FaultyPair{Model: s.model, Position: position}
+
+	switch s.model {
+	case FaultRandomByte:
+		fault.Position = s.rng.Intn(16)
+		fault.Mask = byte(1 + s.rng.Intn(255))
+		pair.Position = -1
+	case FaultKnownPosition:
+		fault.Mask = byte(1 + s.rng.Intn(255))
+	case FaultSingleBit:
+		fault.Mask = 1 << s.rng.Intn(8)
+	}
+
+	pair.Correct = s.cipher.EncryptBlock(plaintext)
+	pair.Faulty = EncryptBlockWithFault(plaintext, s.cipher.expandedKey, fault)
+	return pair
+}
+
+// mixColumnsCoef é a matriz do MixColumns: M[i][j] = base[(j-i) mod 4].
+func mixColumnsCoef(i, j int) byte {
+	base := [4]byte{0x02, 0x03, 0x01, 0x01}
+	return base[(j-i+4)%4]
+}
+
+// ciphertextPosition diz onde o byte da linha i da coluna c (antes da
+// última ShiftRows) aparece no ciphertext.
+func ciphertextPosition(c, i int) int {
+	return 4*((c-i+4)%4) + i
+}
+
+// faultColumn identifica a coluna da falha pelos bytes que diferem.
+func faultColumn(pair FaultyPair) (int, error) {
+	col := -1
+	diffs := 0
+	for p := 0; p < BlockSize; p++ {
+		if pair.Correct[p] == pair.Faulty[p] {
+			continue
+		}
+		diffs++
+		c := (p/4 + p%4) % 4
+		if col >= 0 && c != col {
+			return 0, errors.New("aes-dfa: diferenças em mais de uma coluna")
+		}
+		col = c
+	}
+	if diffs != 4 {
+		return 0, fmt.Errorf("aes-dfa: esperado 4 bytes diferentes, encontrado %d", diffs)
+	}
+	return col, nil
+}
+
+// columnCandidates devolve os valores possíveis (4 bytes de K10 empacotados
+// em um uint32) compatíveis com um par defeituoso na coluna col.
+func columnCandidates(pair FaultyPair, col int) map[uint32]bool {
+	rows := []int{0, 1, 2, 3}
+	if pair.Position >= 0 {
+		rows = []int{pair.Position % 4}
+	}
+
+	var faults []byte
+	if pair.Model == FaultSingleBit {
+		for b := 0; b < 8; b++ {
+			faults = append(faults, 1<<b)
+		}
+	} else {
+		for e := 1; e < 256; e++ {
+			faults = append(faults, byte(e))
+		}
+	}
+
+	// byDelta[i][d] = chaves k tais que InvSbox(C^k) ^ InvSbox(C'^k) = d
+	var byDelta [4][256][]byte
+	for i := 0; i < 4; i++ {
+		p := ciphertextPosition(col, i)
+		for k := 0; k < 256; k++ {
+			d := invSbox[pair.Correct[p]^byte(k)] ^ invSbox[pair.Faulty[p]^byte(k)]
+			byDelta[i][d] = append(byDelta[i][d], byte(k))
+		}
+	}
+
+	candidates := make(map[uint32]bool)
+	for _, r := range rows {
+		for _, e := range faults {
+			var lists [4][]byte
+			empty := false
+			for i := 0; i < 4; i++ {
+				lists[i] = byDelta[i][gmul(mixColumnsCoef(i, r), e)]
+				if len(lists[i]) == 0 {
+					empty = true
+					break
+				}
+			}
+			if empty {
+				continue
+			}
+			for _, k0 := range lists[0] {
+				for _, k1 := range lists[1] {
+					for _, k2 := range lists[2] {
+						for _, k3 := range lists[3] {
+							candidates[uint32(k0)<<24|uint32(k1)<<16|uint32(k2)<<8|uint32(k3)] = true
+						}
+					}
+				}
+			}
+		}
+	}
+	return candidates
+}
+
+/*
+DFALastRoundKey: Ataque de Piret-Quisquater. Recebe pares (correto,
+defeituoso) com falhas na rodada Nr-1 e devolve a última chave de rodada.
+
+	São necessários pares em todas as 4 colunas; no modelo de byte
+	aleatório isso costuma exigir de 8 a 12 falhas.
+*/
+func DFALastRoundKey(pairs []FaultyPair) ([]byte, error) {
+	var columns [4]map[uint32]bool
+
+	for _, pair := range pairs {
+		col, err := faultColumn(pair)
+		if err != nil {
+			return nil, err
+		}
+
+		cands := columnCandidates(pair, col)
+		if columns[col] == nil {
+			columns[col] = cands
+			continue
+		}
+		for k := range columns[col] {
+			if !cands[k] {
+				delete(columns[col], k)
+			}
+		}
+	}
+
+	key := make([]byte, BlockSize)
+	for col, cands := range columns {
+		if len(cands) != 1 {
+			return nil, fmt.Errorf("aes-dfa: coluna %d com %d candidatos, são necessários mais pares", col, len(cands))
+		}
+		for k := range cands {
+			for i := 0; i < 4; i++ {
+				key[ciphertextPosition(col, i)] = byte(k >> (24 - 8*i))
+			}
+		}
+	}
+	return key, nil
+}
+
+// DFARecoverKey recupera a chave de um AES-128 a partir dos pares defeituosos.
+func DFARecoverKey(pairs []FaultyPair) ([]byte, error) {
+	lastRoundKey, err := DFALastRoundKey(pairs)
+	if err != nil {
+		return nil, err
+	}
+	return RecoverKey(lastRoundKey, 10)
+}
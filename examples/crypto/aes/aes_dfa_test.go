@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestEncryptBlockWithFault(t *testing.T) {
+	key := mustHex(t, "000102030405060708090a0b0c0d0e0f")
+	plaintext := mustHex(t, "00112233445566778899aabbccddeeff")
+	w := KeyExpansion(key)
+
+	// Sem falha (Mask = 0) o resultado é o do FIPS-197
+	if got := EncryptBlockWithFault(plaintext, w, Fault{Round: 9}); !bytes.Equal(got, mustHex(t, "69c4e0d86a7b0430d8cdb78070b4c55a")) {
+		t.Fatalf("sem falha: %x", got)
+	}
+
+	// Uma falha na rodada 9 afeta exatamente 4 bytes, um em cada linha
+	for pos := 0; pos < 16; pos++ {
+		faulty := EncryptBlockWithFault(plaintext, w, Fault{Round: 9, Position: pos, Mask: 0x5a})
+		col, err := faultColumn(FaultyPair{Correct: EncryptBlock(plaintext, w), Faulty: faulty})
+		if err != nil {
+			t.Fatalf("posição %d: %v", pos, err)
+		}
+		if col != pos/4 {
+			t.Fatalf("posição %d: coluna %d, esperado %d", pos, col, pos/4)
+		}
+	}
+}
+
+func TestDFAModelos(t *testing.T) {
+	for _, model := range []FaultModel{FaultRandomByte, FaultKnownPosition, FaultSingleBit} {
+		t.Run(model.String(), func(t *testing.T) {
+			rng := rand.New(rand.NewSource(int64(model) + 10))
+			key := make([]byte, 16)
+			rng.Read(key)
+
+			sim, err := NewFaultSimulator(key, model, int64(model))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var pairs []FaultyPair
+			for n := 1; n <= 64; n++ {
+				plaintext := make([]byte, 16)
+				rng.Read(plaintext)
+				// No modelo de posição escolhida o atacante percorre as 4 colunas
+				pairs = append(pairs, sim.Inject(plaintext, 4*(n%4)))
+
+				got, err := DFARecoverKey(pairs)
+				if err != nil {
+					continue
+				}
+				if !bytes.Equal(got, key) {
+					t.Fatalf("chave recuperada %x, esperado %x", got, key)
+				}
+				t.Logf("chave recuperada com %d falhas", n)
+				return
+			}
+			t.Fatalf("chave não recuperada com %d falhas", len(pairs))
+		})
+	}
+}
+
+func TestFaultSimulatorSoAES128(t *testing.T) {
+	for _, size := range []int{24, 32} {
+		if _, err := NewFaultSimulator(make([]byte, size), FaultRandomByte, 1); err == nil {
+			t.Errorf("chave de %d bytes aceita", size)
+		}
+	}
+}
+
+func TestInjectPosicaoInvalida(t *testing.T) {
+	plaintext := make([]byte, 16)
+	for _, model := range []FaultModel{FaultKnownPosition, FaultSingleBit} {
+		sim, _ := NewFaultSimulator(make([]byte, 16), model, 1)
+		for _, pos := range []int{-1, 16, 100} {
+			func() {
+				defer func() {
+					r := recover()
+					if msg, ok := r.(string); !ok || !strings.HasPrefix(msg, "aes-dfa: ") {
+						t.Errorf("%v, posição %d: esperado panic aes-dfa, obtido %v", model, pos, r)
+					}
+				}()
+				sim.Inject(plaintext, pos)
+			}()
+		}
+	}
+
+	// No modelo de byte aleatório a posição é ignorada
+	sim, _ := NewFaultSimulator(make([]byte, 16), FaultRandomByte, 1)
+	sim.Inject(plaintext, 100)
+}
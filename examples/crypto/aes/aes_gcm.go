@@ -0,0 +1,279 @@
+/*
+
+AES-GCM (Galois/Counter Mode) - NIST SP 800-38D
+
+GCM combina o modo CTR (confidencialidade) com GHASH (autenticidade), uma
+função de hash universal sobre GF(2^128). O resultado é uma cifra AEAD:
+cifra o plaintext e autentica ciphertext + dados adicionais (AAD).
+
+	H  = E_k(0^128)                         chave do GHASH
+	J0 = IV || 0^31 || 1                    se len(IV) == 96 bits
+	J0 = GHASH_H(IV || 0^s || 0^64 || [len(IV)]_64)   caso contrário
+
+	C  = GCTR_k(inc32(J0), P)               CTR com incremento nos 32 bits finais
+	S  = GHASH_H(A || 0^v || C || 0^u || [len(A)]_64 || [len(C)]_64)
+	T  = E_k(J0) XOR S                      tag de 128 bits
+
+*/
+
+package main
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+const (
+	gcmStandardNonceSize = 12
+	gcmTagSize           = 16
+)
+
+var errOpen = errors.New("aes-gcm: falha na autenticação da mensagem")
+
+// GCM implementa cipher.AEAD sobre o AES deste diretório.
+type GCM struct {
+	block *AES
+	h     fieldElement // H = E_k(0^128)
+}
+
+// fieldElement é um elemento de GF(2^128) na convenção do GCM: o bit mais à
+// esquerda de hi é o coeficiente de x^0 e o bit mais à direita de lo é o de x^127.
+type fieldElement struct {
+	hi, lo uint64
+}
+
+/*
+NewGCM: Cria uma instância AES-GCM a partir de uma chave de 16, 24 ou 32 bytes.
+
+	O nonce recomendado tem 96 bits (NonceSize), mas Seal e Open aceitam nonces
+	de qualquer tamanho não nulo, processados via GHASH como na seção 7.1 do
+	SP 800-38D.
+*/
+func NewGCM(key []byte) (*GCM, error) {
+	a, err := New(key)
+	if err != nil {
+		return nil, err
+	}
+
+	var zero, h [BlockSize]byte
+	encryptBlock(h[:], zero[:], a.roundKeys, nil)
+
+	return &GCM{block: a, h: loadFieldElement(h[:])}, nil
+}
+
+func (g *GCM) NonceSize() int {
+	return gcmStandardNonceSize
+}
+
+func (g *GCM) Overhead() int {
+	return gcmTagSize
+}
+
+/*
+Seal: Cifra e autentica plaintext, autentica additionalData e anexa o
+resultado (ciphertext || tag) a dst.
+*/
+func (g *GCM) Seal(dst, nonce, plaintext, additionalData []byte) []byte {
+	if len(nonce) == 0 {
+		panic("aes-gcm: nonce vazio")
+	}
+
+	j0 := g.deriveCounter(nonce)
+
+	ret, out := sliceForAppend(dst, len(plaintext)+gcmTagSize)
+	ciphertext := out[:len(plaintext)]
+
+	counter := j0
+	inc32(&counter)
+	g.counterCrypt(ciphertext, plaintext, &counter)
+
+	tag := g.computeTag(&j0, ciphertext, additionalData)
+	copy(out[len(plaintext):], tag[:])
+	return ret
+}
+
+/*
+Open: Verifica a tag e, somente se ela for válida, decifra o ciphertext.
+
+	A comparação da tag é feita em tempo constante: todos os 16 bytes são
+	sempre comparados, de modo que o tempo de resposta não revela quantos
+	bytes iniciais da tag estavam corretos.
+*/
+func (g *GCM) Open(dst, nonce, ciphertext, additionalData []byte) ([]byte, error) {
+	if len(nonce) == 0 {
+		panic("aes-gcm: nonce vazio")
+	}
+	if len(ciphertext) < gcmTagSize {
+		return nil, errOpen
+	}
+
+	tag := ciphertext[len(ciphertext)-gcmTagSize:]
+	ciphertext = ciphertext[:len(ciphertext)-gcmTagSize]
+
+	j0 := g.deriveCounter(nonce)
+	expectedTag := g.computeTag(&j0, ciphertext, additionalData)
+
+	if !constantTimeEqual(expectedTag[:], tag) {
+		return nil, errOpen
+	}
+
+	ret, out := sliceForAppend(dst, len(ciphertext))
+	counter := j0
+	inc32(&counter)
+	g.counterCrypt(out, ciphertext, &counter)
+	return ret, nil
+}
+
+// deriveCounter calcula J0 a partir do nonce.
+func (g *GCM) deriveCounter(nonce []byte) [BlockSize]byte {
+	var j0 [BlockSize]byte
+
+	if len(nonce) == gcmStandardNonceSize {
+		copy(j0[:], nonce)
+		j0[BlockSize-1] = 1
+		return j0
+	}
+
+	var y fieldElement
+	g.ghashUpdate(&y, nonce)
+
+	var lengths [BlockSize]byte
+	binary.BigEndian.PutUint64(lengths[8:], uint64(len(nonce))*8)
+	g.ghashUpdate(&y, lengths[:])
+
+	binary.BigEndian.PutUint64(j0[:8], y.hi)
+	binary.BigEndian.PutUint64(j0[8:], y.lo)
+	return j0
+}
+
+// counterCrypt é o GCTR: cifra o contador e faz XOR com a entrada, incrementando
+// apenas os 32 bits menos significativos do contador a cada bloco.
+func (g *GCM) counterCrypt(out, in []byte, counter *[BlockSize]byte) {
+	var keystream [BlockSize]byte
+
+	for len(in) > 0 {
+		encryptBlock(keystream[:], counter[:], g.block.roundKeys, nil)
+		inc32(counter)
+
+		n := min(len(in), BlockSize)
+		for i := 0; i < n; i++ {
+			out[i] = in[i] ^ keystream[i]
+		}
+		in, out = in[n:], out[n:]
+	}
+}
+
+// computeTag calcula T = E_k(J0) XOR GHASH_H(A, C).
+func (g *GCM) computeTag(j0 *[BlockSize]byte, ciphertext, additionalData []byte) [gcmTagSize]byte {
+	var y fieldElement
+	g.ghashUpdate(&y, additionalData)
+	g.ghashUpdate(&y, ciphertext)
+
+	var lengths [BlockSize]byte
+	binary.BigEndian.PutUint64(lengths[:8], uint64(len(additionalData))*8)
+	binary.BigEndian.PutUint64(lengths[8:], uint64(len(ciphertext))*8)
+	g.ghashUpdate(&y, lengths[:])
+
+	var tag [gcmTagSize]byte
+	encryptBlock(tag[:], j0[:], g.block.roundKeys, nil)
+	binary.BigEndian.PutUint64(tag[:8], binary.BigEndian.Uint64(tag[:8])^y.hi)
+	binary.BigEndian.PutUint64(tag[8:], binary.BigEndian.Uint64(tag[8:])^y.lo)
+	return tag
+}
+
+// ghashUpdate processa data em blocos de 16 bytes (o último completado com
+// zeros): Y = (Y XOR Xi) * H.
+func (g *GCM) ghashUpdate(y *fieldElement, data []byte) {
+	for len(data) > 0 {
+		var block [BlockSize]byte
+		n := copy(block[:], data)
+		data = data[n:]
+
+		x := loadFieldElement(block[:])
+		y.hi ^= x.hi
+		y.lo ^= x.lo
+		*y = gfMul128(*y, g.h)
+	}
+}
+
+func loadFieldElement(b []byte) fieldElement {
+	return fieldElement{
+		hi: binary.BigEndian.Uint64(b[:8]),
+		lo: binary.BigEndian.Uint64(b[8:16]),
+	}
+}
+
+/*
+gfMul128: Multiplica dois elementos de GF(2^128) módulo x^128 + x^7 + x^2 + x + 1
+(SP 800-38D, algoritmo 1).
+
+	Como no GCM os bits são "refletidos" (x^0 à esquerda), multiplicar V por x
+	é um shift para a DIREITA, e a redução soma R = 11100001 || 0^120 quando
+	o bit x^127 (o mais à direita) sai do registrador.
+
+	Z = 0, V = Y
+	para i = 0..127:
+		se bit i de X == 1: Z = Z XOR V
+		se bit 127 de V == 0: V = V >> 1
+		senão:                V = (V >> 1) XOR R
+
+	Os "se" são feitos com máscaras para que o tempo não dependa dos bits de
+	H ou dos dados, ao contrário de xtime em aes.go, que usa um if.
+*/
+func gfMul128(x, y fieldElement) fieldElement {
+	var z fieldElement
+	v := y
+
+	for i := 0; i < 128; i++ {
+		var bit uint64
+		if i < 64 {
+			bit = (x.hi >> (63 - i)) & 1
+		} else {
+			bit = (x.lo >> (127 - i)) & 1
+		}
+		mask := -bit // 0x000... ou 0xfff...
+		z.hi ^= v.hi & mask
+		z.lo ^= v.lo & mask
+
+		reduce := -(v.lo & 1)
+		v.lo = v.lo>>1 | v.hi<<63
+		v.hi = v.hi>>1 ^ (0xe100000000000000 & reduce)
+	}
+	return z
+}
+
+// inc32 incrementa os 32 bits finais do contador (módulo 2^32).
+func inc32(counter *[BlockSize]byte) {
+	c := binary.BigEndian.Uint32(counter[BlockSize-4:])
+	binary.BigEndian.PutUint32(counter[BlockSize-4:], c+1)
+}
+
+/*
+constantTimeEqual: Compara dois slices sem sair no primeiro byte diferente.
+
+	Uma comparação ingênua (bytes.Equal) retorna assim que encontra uma
+	diferença, e o tempo de resposta permite descobrir a tag byte a byte.
+	Aqui todas as diferenças são acumuladas com OR e só o final é testado.
+*/
+func constantTimeEqual(a, b []byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	var diff byte
+	for i := range a {
+		diff |= a[i] ^ b[i]
+	}
+	return diff == 0
+}
+
+// sliceForAppend estende in com n bytes, retornando o slice completo e a parte nova.
+func sliceForAppend(in []byte, n int) (head, tail []byte) {
+	if total := len(in) + n; cap(in) >= total {
+		head = in[:total]
+	} else {
+		head = make([]byte, total)
+		copy(head, in)
+	}
+	tail = head[len(in):]
+	return
+}
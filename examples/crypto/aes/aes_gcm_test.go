@@ -0,0 +1,205 @@
+package main
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"math/rand"
+	"testing"
+)
+
+var _ cipher.AEAD = (*GCM)(nil)
+
+// Vetores de "The Galois/Counter Mode of Operation (GCM)", McGrew e Viega,
+// apêndice B (casos 1 a 6 com AES-128 e 13 a 18 com AES-256).
+var gcmVetores = []struct {
+	nome       string
+	key        string
+	plaintext  string
+	aad        string
+	iv         string
+	ciphertext string
+	tag        string
+}{
+	{
+		"caso 1", "00000000000000000000000000000000", "", "",
+		"000000000000000000000000", "", "58e2fccefa7e3061367f1d57a4e7455a",
+	},
+	{
+		"caso 2", "00000000000000000000000000000000", "00000000000000000000000000000000", "",
+		"000000000000000000000000", "0388dace60b6a392f328c2b971b2fe78", "ab6e47d42cec13bdf53a67b21257bddf",
+	},
+	{
+		"caso 3", "feffe9928665731c6d6a8f9467308308",
+		"d9313225f88406e5a55909c5aff5269a86a7a9531534f7da2e4c303d8a318a721c3c0c95956809532fcf0e2449a6b525b16aedf5aa0de657ba637b391aafd255",
+		"", "cafebabefacedbaddecaf888",
+		"42831ec2217774244b7221b784d0d49ce3aa212f2c02a4e035c17e2329aca12e21d514b25466931c7d8f6a5aac84aa051ba30b396a0aac973d58e091473f5985",
+		"4d5c2af327cd64a62cf35abd2ba6fab4",
+	},
+	{
+		"caso 4", "feffe9928665731c6d6a8f9467308308",
+		"d9313225f88406e5a55909c5aff5269a86a7a9531534f7da2e4c303d8a318a721c3c0c95956809532fcf0e2449a6b525b16aedf5aa0de657ba637b39",
+		"feedfacedeadbeeffeedfacedeadbeefabaddad2", "cafebabefacedbaddecaf888",
+		"42831ec2217774244b7221b784d0d49ce3aa212f2c02a4e035c17e2329aca12e21d514b25466931c7d8f6a5aac84aa051ba30b396a0aac973d58e091",
+		"5bc94fbc3221a5db94fae95ae7121a47",
+	},
+	{
+		"caso 5 (IV de 64 bits)", "feffe9928665731c6d6a8f9467308308",
+		"d9313225f88406e5a55909c5aff5269a86a7a9531534f7da2e4c303d8a318a721c3c0c95956809532fcf0e2449a6b525b16aedf5aa0de657ba637b39",
+		"feedfacedeadbeeffeedfacedeadbeefabaddad2", "cafebabefacedbad",
+		"61353b4c2806934a777ff51fa22a4755699b2a714fcdc6f83766e5f97b6c742373806900e49f24b22b097544d4896b424989b5e1ebac0f07c23f4598",
+		"3612d2e79e3b0785561be14aaca2fccb",
+	},
+	{
+		"caso 6 (IV de 480 bits)", "feffe9928665731c6d6a8f9467308308",
+		"d9313225f88406e5a55909c5aff5269a86a7a9531534f7da2e4c303d8a318a721c3c0c95956809532fcf0e2449a6b525b16aedf5aa0de657ba637b39",
+		"feedfacedeadbeeffeedfacedeadbeefabaddad2",
+		"9313225df88406e555909c5aff5269aa6a7a9538534f7da1e4c303d2a318a728c3c0c95156809539fcf0e2429a6b525416aedbf5a0de6a57a637b39b",
+		"8ce24998625615b603a033aca13fb894be9112a5c3a211a8ba262a3cca7e2ca701e4a9a4fba43c90ccdcb281d48c7c6fd62875d2aca417034c34aee5",
+		"619cc5aefffe0bfa462af43c1699d050",
+	},
+	{
+		"caso 13", "0000000000000000000000000000000000000000000000000000000000000000", "", "",
+		"000000000000000000000000", "", "530f8afbc74536b9a963b4f1c4cb738b",
+	},
+	{
+		"caso 14", "0000000000000000000000000000000000000000000000000000000000000000",
+		"00000000000000000000000000000000", "", "000000000000000000000000",
+		"cea7403d4d606b6e074ec5d3baf39d18", "d0d1c8a799996bf0265b98b5d48ab919",
+	},
+	{
+		"caso 16", "feffe9928665731c6d6a8f9467308308feffe9928665731c6d6a8f9467308308",
+		"d9313225f88406e5a55909c5aff5269a86a7a9531534f7da2e4c303d8a318a721c3c0c95956809532fcf0e2449a6b525b16aedf5aa0de657ba637b39",
+		"feedfacedeadbeeffeedfacedeadbeefabaddad2", "cafebabefacedbaddecaf888",
+		"522dc1f099567d07f47f37a32a84427d643a8cdcbfe5c0c97598a2bd2555d1aa8cb08e48590dbb3da7b08b1056828838c5f61e6393ba7a0abcc9f662",
+		"76fc6ece0f4e1768cddf8853bb2d551b",
+	},
+}
+
+func TestGCMVetores(t *testing.T) {
+	for _, v := range gcmVetores {
+		t.Run(v.nome, func(t *testing.T) {
+			g, err := NewGCM(mustHex(t, v.key))
+			if err != nil {
+				t.Fatal(err)
+			}
+			plaintext := mustHex(t, v.plaintext)
+			aad := mustHex(t, v.aad)
+			iv := mustHex(t, v.iv)
+			expected := append(mustHex(t, v.ciphertext), mustHex(t, v.tag)...)
+
+			sealed := g.Seal(nil, iv, plaintext, aad)
+			if !bytes.Equal(sealed, expected) {
+				t.Fatalf("Seal: obtido %x, esperado %x", sealed, expected)
+			}
+
+			opened, err := g.Open(nil, iv, sealed, aad)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(opened, plaintext) {
+				t.Fatalf("Open: obtido %x, esperado %x", opened, plaintext)
+			}
+		})
+	}
+}
+
+// Compara com crypto/cipher.NewGCM para vários tamanhos de chave, nonce,
+// plaintext e AAD.
+func TestGCMComparaComCryptoCipher(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for _, keySize := range []int{16, 24, 32} {
+		for _, nonceSize := range []int{1, 8, 12, 16, 60} {
+			key := make([]byte, keySize)
+			rng.Read(key)
+
+			nosso, err := NewGCM(key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			block, _ := aes.NewCipher(key)
+			ref, err := cipher.NewGCMWithNonceSize(block, nonceSize)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, n := range []int{0, 1, 15, 16, 17, 64, 100} {
+				nonce := make([]byte, nonceSize)
+				plaintext := make([]byte, n)
+				aad := make([]byte, rng.Intn(40))
+				rng.Read(nonce)
+				rng.Read(plaintext)
+				rng.Read(aad)
+
+				got := nosso.Seal(nil, nonce, plaintext, aad)
+				want := ref.Seal(nil, nonce, plaintext, aad)
+				if !bytes.Equal(got, want) {
+					t.Fatalf("chave=%d nonce=%d n=%d: obtido %x, esperado %x", keySize, nonceSize, n, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestGCMRejeitaAdulteracao(t *testing.T) {
+	g, err := NewGCM(mustHex(t, "feffe9928665731c6d6a8f9467308308"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonce := mustHex(t, "cafebabefacedbaddecaf888")
+	aad := []byte("cabeçalho")
+	sealed := g.Seal(nil, nonce, []byte("mensagem autenticada"), aad)
+
+	for i := range sealed {
+		adulterado := append([]byte(nil), sealed...)
+		adulterado[i] ^= 0x01
+		if out, err := g.Open(nil, nonce, adulterado, aad); err == nil || out != nil {
+			t.Fatalf("byte %d adulterado foi aceito", i)
+		}
+	}
+
+	if _, err := g.Open(nil, nonce, sealed, []byte("outro cabeçalho")); err == nil {
+		t.Fatal("AAD diferente foi aceito")
+	}
+	if _, err := g.Open(nil, nonce, sealed[:gcmTagSize-1], aad); err == nil {
+		t.Fatal("ciphertext menor que a tag foi aceito")
+	}
+}
+
+func TestGCMAppend(t *testing.T) {
+	g, _ := NewGCM(make([]byte, 16))
+	nonce := make([]byte, gcmStandardNonceSize)
+	prefix := []byte("prefixo:")
+
+	sealed := g.Seal(append([]byte(nil), prefix...), nonce, []byte("dados"), nil)
+	if !bytes.HasPrefix(sealed, prefix) || len(sealed) != len(prefix)+len("dados")+g.Overhead() {
+		t.Fatalf("Seal não anexou ao dst: %x", sealed)
+	}
+
+	opened, err := g.Open(append([]byte(nil), prefix...), nonce, sealed[len(prefix):], nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(opened) != "prefixo:dados" {
+		t.Fatalf("Open não anexou ao dst: %q", opened)
+	}
+}
+
+func TestConstantTimeEqual(t *testing.T) {
+	casos := []struct {
+		a, b []byte
+		eq   bool
+	}{
+		{[]byte{1, 2, 3}, []byte{1, 2, 3}, true},
+		{[]byte{1, 2, 3}, []byte{1, 2, 4}, false},
+		{[]byte{0, 2, 3}, []byte{1, 2, 3}, false},
+		{[]byte{1, 2}, []byte{1, 2, 3}, false},
+		{nil, nil, true},
+	}
+	for _, c := range casos {
+		if got := constantTimeEqual(c.a, c.b); got != c.eq {
+			t.Errorf("constantTimeEqual(%x, %x) = %v", c.a, c.b, got)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+/*
+
+Inversão do key schedule do AES
+
+A expansão da chave (KeyExpansion) é uma recorrência invertível:
+
+	w[i] = w[i-Nk] ^ T(w[i-1])          (indo para frente)
+	w[i-Nk] = w[i] ^ T(w[i-1])          (indo para trás)
+
+onde T é RotWord+SubWord+Rcon quando i%Nk == 0, SubWord quando Nk = 8 e
+i%Nk == 4, e a identidade nos demais casos.
+
+Assim, conhecendo Nk palavras CONSECUTIVAS em qualquer posição, é possível
+reconstruir toda a chave expandida e a chave original. Isso é o que permite
+aos ataques de criptoanálise (DFA, ataque square) recuperar apenas a última
+chave de rodada e, a partir dela, a chave mestra.
+
+	AES-128: Nk = 4 palavras = exatamente uma chave de rodada
+	AES-192: Nk = 6 palavras = uma chave de rodada + metade da seguinte
+	AES-256: Nk = 8 palavras = duas chaves de rodada consecutivas
+
+Para AES-192/256 uma única chave de rodada (128 bits) não basta: ela deixa
+64 ou 128 bits da chave sem nenhuma restrição.
+
+*/
+
+package main
+
+import "fmt"
+
+// scheduleTemp calcula T(w[i-1]) da expansão da chave para a posição i.
+func scheduleTemp(prev [4]byte, i, nk int) [4]byte {
+	if i%nk == 0 {
+		temp := SubWord(RotWord(prev))
+		temp[0] ^= rcon[(i/nk)-1]
+		return temp
+	}
+	if nk > 6 && i%nk == 4 {
+		return SubWord(prev)
+	}
+	return prev
+}
+
+/*
+InvertKeyExpansion: Reconstrói a chave expandida completa a partir de Nk
+palavras consecutivas w[start], ..., w[start+Nk-1].
+
+	O tamanho da chave (Nk = 4, 6 ou 8) é deduzido de len(words).
+	O resultado é idêntico ao de KeyExpansion(chave original), e a chave
+	original são as primeiras Nk palavras.
+*/
+func InvertKeyExpansion(words [][4]byte, start int) ([][4]byte, error) {
+	nk := len(words)
+	switch nk {
+	case 4, 6, 8:
+	default:
+		return nil, fmt.Errorf("aes: são necessárias 4, 6 ou 8 palavras consecutivas, recebido %d", nk)
+	}
+
+	total := Nb * (nk + 6 + 1)
+	if start < 0 || start+nk > total {
+		return nil, fmt.Errorf("aes: palavras %d..%d fora da chave expandida (0..%d)", start, start+nk-1, total-1)
+	}
+
+	w := make([][4]byte, total)
+	copy(w[start:], words)
+
+	// Para trás: w[i-Nk] = w[i] ^ T(w[i-1])
+	for i := start + nk - 1; i-nk >= 0; i-- {
+		temp := scheduleTemp(w[i-1], i, nk)
+		for j := 0; j < 4; j++ {
+			w[i-nk][j] = w[i][j] ^ temp[j]
+		}
+	}
+
+	// Para frente: w[i] = w[i-Nk] ^ T(w[i-1])
+	for i := start + nk; i < total; i++ {
+		temp := scheduleTemp(w[i-1], i, nk)
+		for j := 0; j < 4; j++ {
+			w[i][j] = w[i-nk][j] ^ temp[j]
+		}
+	}
+	return w, nil
+}
+
+/*
+RecoverKey: Recupera a chave original a partir da(s) chave(s) de rodada.
+
+	roundKeys são os bytes como usados em AddRoundKey, começando na rodada
+	round: 16 bytes para AES-128, 24 para AES-192 (rodada round e metade
+	da seguinte) e 32 para AES-256 (rodadas round e round+1).
+
+	Exemplo (FIPS-197, apêndice A.1): a chave da rodada 10
+	d014f9a8c9ee2589e13f0cc8b6630ca6 leva de volta a
+	2b7e151628aed2a6abf7158809cf4f3c.
+*/
+func RecoverKey(roundKeys []byte, round int) ([]byte, error) {
+	if len(roundKeys)%4 != 0 {
+		return nil, fmt.Errorf("aes: tamanho inválido para chaves de rodada: %d bytes", len(roundKeys))
+	}
+
+	words := make([][4]byte, len(roundKeys)/4)
+	for i := range words {
+		copy(words[i][:], roundKeys[4*i:])
+	}
+
+	w, err := InvertKeyExpansion(words, round*Nb)
+	if err != nil {
+		return nil, err
+	}
+
+	key := make([]byte, len(roundKeys))
+	for i := range words {
+		copy(key[4*i:], w[i][:])
+	}
+	return key, nil
+}
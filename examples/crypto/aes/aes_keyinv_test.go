@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+)
+
+// Para cada tamanho de chave e cada posição possível, Nk palavras
+// consecutivas de KeyExpansion devem reconstruir a chave expandida inteira.
+func TestInvertKeyExpansionIdaEVolta(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for _, keySize := range []int{16, 24, 32} {
+		for trial := 0; trial < 20; trial++ {
+			key := make([]byte, keySize)
+			rng.Read(key)
+			w := KeyExpansion(key)
+			nk := keySize / 4
+
+			for start := 0; start+nk <= len(w); start++ {
+				got, err := InvertKeyExpansion(w[start:start+nk], start)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(got) != len(w) {
+					t.Fatalf("tamanho %d, esperado %d", len(got), len(w))
+				}
+				for i := range w {
+					if got[i] != w[i] {
+						t.Fatalf("chave %x, início %d: palavra %d = %x, esperado %x", key, start, i, got[i], w[i])
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestRecoverKeyUltimaRodada(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+
+	for _, keySize := range []int{16, 24, 32} {
+		key := make([]byte, keySize)
+		rng.Read(key)
+		a, _ := New(key)
+		w := KeyExpansion(key)
+
+		// Rodadas cujas Nk palavras ainda cabem na chave expandida
+		for round := 0; round*Nb+a.Nk <= len(w); round++ {
+			roundKeys := flattenWords(w[round*Nb : round*Nb+a.Nk])
+			got, err := RecoverKey(roundKeys, round)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, key) {
+				t.Fatalf("AES-%d rodada %d: obtido %x, esperado %x", keySize*8, round, got, key)
+			}
+		}
+	}
+}
+
+func TestRecoverKeyApendiceA(t *testing.T) {
+	got, err := RecoverKey(mustHex(t, "d014f9a8c9ee2589e13f0cc8b6630ca6"), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := mustHex(t, "2b7e151628aed2a6abf7158809cf4f3c"); !bytes.Equal(got, want) {
+		t.Fatalf("obtido %x, esperado %x", got, want)
+	}
+}
+
+func TestInvertKeyExpansionErros(t *testing.T) {
+	if _, err := InvertKeyExpansion(make([][4]byte, 5), 0); err == nil {
+		t.Error("5 palavras deveriam ser rejeitadas")
+	}
+	if _, err := InvertKeyExpansion(make([][4]byte, 4), 41); err == nil {
+		t.Error("início 41 em AES-128 deveria ser rejeitado")
+	}
+	if _, err := RecoverKey(make([]byte, 16), 11); err == nil {
+		t.Error("rodada 11 em AES-128 deveria ser rejeitada")
+	}
+	if _, err := RecoverKey(make([]byte, 32), 14); err == nil {
+		t.Error("AES-256 com rodadas 14 e 15 deveria ser rejeitado")
+	}
+}
+
+func flattenWords(words [][4]byte) []byte {
+	out := make([]byte, 0, 4*len(words))
+	for _, w := range words {
+		out = append(out, w[:]...)
+	}
+	return out
+}
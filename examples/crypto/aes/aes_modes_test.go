@@ -0,0 +1,39 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/osdeving/modes"
+)
+
+// O AES deste diretório, via NewCipher, funciona com o pacote de modos de
+// operação (NIST SP 800-38A, F.2.1 CBC-AES128).
+func TestCBCComAESDoRepositorio(t *testing.T) {
+	key, _ := hex.DecodeString("2b7e151628aed2a6abf7158809cf4f3c")
+	iv, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	plaintext, _ := hex.DecodeString("6bc1bee22e409f96e93d7e117393172aae2d8a571e03ac9c9eb76fac45af8e51")
+	expected, _ := hex.DecodeString("7649abac8119b246cee98e9b12e9197d5086cb9b507219ee95db113a917678b2")
+
+	block, err := NewCipher(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ciphertext, err := modes.EncryptCBC(block, iv, plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(ciphertext, expected) {
+		t.Errorf("CBC: obtido %x, esperado %x", ciphertext, expected)
+	}
+
+	dec, err := modes.DecryptCBC(block, iv, ciphertext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(dec, plaintext) {
+		t.Errorf("CBC decifrado: obtido %x, esperado %x", dec, plaintext)
+	}
+}
@@ -0,0 +1,121 @@
+/*
+
+Rijndael completo: blocos e chaves de 128 a 256 bits, S-box substituível
+
+O AES é o Rijndael restrito a blocos de 128 bits (Nb = 4). A submissão
+original aceitava Nb e Nk independentes, cada um de 4 a 8 palavras de 32
+bits (128, 160, 192, 224 ou 256 bits):
+
+	Nr = max(Nb, Nk) + 6
+
+	ShiftRows desloca a linha i por C_i posições, que dependem de Nb:
+
+	+----+----+----+----+
+	| Nb | C1 | C2 | C3 |
+	+----+----+----+----+
+	|  4 |  1 |  2 |  3 |
+	|  5 |  1 |  2 |  3 |
+	|  6 |  1 |  2 |  3 |
+	|  7 |  1 |  2 |  4 |
+	|  8 |  1 |  3 |  4 |
+	+----+----+----+----+
+
+O AES é o caso Nb = 4 das mesmas funções: expandKey, subBytes, shiftRows
+(com shiftOffsets), mixColumns, addRoundKey e o laço de encryptRounds e
+decryptRounds (aes.go) recebem o state como 4 linhas de Nb bytes. O
+Rijndael só escolhe Nb e a S-box (ver aes_sboxgen.go); com a S-box padrão e
+Nb = 4 o resultado é idêntico ao AES.
+
+*/
+
+package main
+
+import "fmt"
+
+// RijndaelState é o state com até MaxNb colunas; só as Nb primeiras são usadas.
+type RijndaelState [4][MaxNb]byte
+
+// rows devolve as nb primeiras colunas de cada linha.
+func (s *RijndaelState) rows(nb int) [4][]byte {
+	return [4][]byte{s[0][:nb], s[1][:nb], s[2][:nb], s[3][:nb]}
+}
+
+// RijndaelSizeError indica bloco ou chave fora de 16, 20, 24, 28 ou 32 bytes.
+type RijndaelSizeError struct {
+	Param string
+	Size  int
+}
+
+func (e RijndaelSizeError) Error() string {
+	return fmt.Sprintf("rijndael: tamanho de %s inválido: %d bytes (use 16, 20, 24, 28 ou 32)", e.Param, e.Size)
+}
+
+// Rijndael implementa cipher.Block com Nb, Nk e S-box escolhidos pelo usuário.
+type Rijndael struct {
+	nb        int
+	roundKeys []byte
+	sbox      [256]byte
+	invSbox   [256]byte
+}
+
+/*
+NewRijndael: Cria a cifra com bloco de 128 bits, a chave dada e a S-box s.
+Com s == nil usa a S-box do AES.
+*/
+func NewRijndael(key []byte, s *SboxPair) (*Rijndael, error) {
+	return NewRijndaelWithBlockSize(key, BlockSize, s)
+}
+
+/*
+NewRijndaelWithBlockSize: Cria a cifra com bloco e chave de 16, 20, 24, 28
+ou 32 bytes cada. Com s == nil usa a S-box do AES.
+*/
+func NewRijndaelWithBlockSize(key []byte, blockSize int, s *SboxPair) (*Rijndael, error) {
+	if !validRijndaelSize(len(key)) {
+		return nil, RijndaelSizeError{"chave", len(key)}
+	}
+	if !validRijndaelSize(blockSize) {
+		return nil, RijndaelSizeError{"bloco", blockSize}
+	}
+
+	r := &Rijndael{nb: blockSize / 4, sbox: sbox, invSbox: invSbox}
+	if s != nil {
+		r.sbox, r.invSbox = s.Forward, s.Inverse
+	}
+	w := expandKey(key, r.nb, func(word [4]byte) [4]byte { return subWord(word, &r.sbox) })
+	r.roundKeys = flattenKey(w)
+	return r, nil
+}
+
+func validRijndaelSize(n int) bool {
+	return n%4 == 0 && n >= 16 && n <= 32
+}
+
+func (r *Rijndael) BlockSize() int { return 4 * r.nb }
+
+func (r *Rijndael) checkBlocks(dst, src []byte) {
+	if len(src) < r.BlockSize() {
+		panic("rijndael: bloco de entrada incompleto")
+	}
+	if len(dst) < r.BlockSize() {
+		panic("rijndael: bloco de saída incompleto")
+	}
+}
+
+func (r *Rijndael) Encrypt(dst, src []byte) {
+	r.checkBlocks(dst, src)
+	var state RijndaelState
+	rows := state.rows(r.nb)
+	loadState(rows, src)
+	encryptRounds(rows, r.roundKeys, &r.sbox, nil)
+	storeState(dst, rows)
+}
+
+func (r *Rijndael) Decrypt(dst, src []byte) {
+	r.checkBlocks(dst, src)
+	var state RijndaelState
+	rows := state.rows(r.nb)
+	loadState(rows, src)
+	decryptRounds(rows, r.roundKeys, &r.invSbox, nil)
+	storeState(dst, rows)
+}
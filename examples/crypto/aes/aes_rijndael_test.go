@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"math/rand"
+	"testing"
+)
+
+/*
+Vetores de teste do Rijndael para todas as 25 combinações de bloco e chave
+(B. Gladman, "AES and Combined Encryption/Authentication Modes", test
+vectors). Plaintext e chave são prefixos das sequências abaixo; para bloco e
+chave de 128 bits é o exemplo do Apêndice B do FIPS-197.
+*/
+const (
+	rijndaelPT  = "3243f6a8885a308d313198a2e03707344a4093822299f31d0082efa98ec4e6c8"
+	rijndaelKey = "2b7e151628aed2a6abf7158809cf4f3c762e7160f38b4da56a784d9045190cfe"
+)
+
+var rijndaelVectors = []struct {
+	block, key int
+	ct         string
+}{
+	{16, 16, "3925841d02dc09fbdc118597196a0b32"},
+	{16, 20, "231d844639b31b412211cfe93712b880"},
+	{16, 24, "f9fb29aefc384a250340d833b87ebc00"},
+	{16, 28, "8faa8fe4dee9eb17caa4797502fc9d3f"},
+	{16, 32, "1a6e6c2c662e7da6501ffb62bc9e93f3"},
+	{20, 16, "16e73aec921314c29df905432bc8968ab64b1f51"},
+	{20, 20, "0553eb691670dd8a5a5b5addf1aa7450f7a0e587"},
+	{20, 24, "73cd6f3423036790463aa9e19cfcde894ea16623"},
+	{20, 28, "601b5dcd1cf4ece954c740445340bf0afdc048df"},
+	{20, 32, "579e930b36c1529aa3e86628bacfe146942882cf"},
+	{24, 16, "b24d275489e82bb8f7375e0d5fcdb1f481757c538b65148a"},
+	{24, 20, "738dae25620d3d3beff4a037a04290d73eb33521a63ea568"},
+	{24, 24, "725ae43b5f3161de806a7c93e0bca93c967ec1ae1b71e1cf"},
+	{24, 28, "bbfc14180afbf6a36382a061843f0b63e769acdc98769130"},
+	{24, 32, "0ebacf199e3315c2e34b24fcc7c46ef4388aa475d66c194c"},
+	{28, 16, "b0a8f78f6b3c66213f792ffd2a61631f79331407a5e5c8d3793aceb1"},
+	{28, 20, "08b99944edfce33a2acb131183ab0168446b2d15e958480010f545e3"},
+	{28, 24, "be4c597d8f7efe22a2f7e5b1938e2564d452a5bfe72399c7af1101e2"},
+	{28, 28, "ef529598ecbce297811b49bbed2c33bbe1241d6e1a833dbe119569e8"},
+	{28, 32, "02fafc200176ed05deb8edb82a3555b0b10d47a388dfd59cab2f6c11"},
+	{32, 16, "7d15479076b69a46ffb3b3beae97ad8313f622f67fedb487de9f06b9ed9c8f19"},
+	{32, 20, "514f93fb296b5ad16aa7df8b577abcbd484decacccc7fb1f18dc567309ceeffd"},
+	{32, 24, "5d7101727bb25781bf6715b0e6955282b9610e23a43c2eb062699f0ebf5887b2"},
+	{32, 28, "d56c5a63627432579e1dd308b2c8f157b40a4bfb56fea1377b25d3ed3d6dbf80"},
+	{32, 32, "a49406115dfb30a40418aafa4869b7c6a886ff31602a7dd19c889dc64f7e4e7a"},
+}
+
+func TestRijndaelVetores(t *testing.T) {
+	pt := mustHex(t, rijndaelPT)
+	key := mustHex(t, rijndaelKey)
+
+	for _, v := range rijndaelVectors {
+		r, err := NewRijndaelWithBlockSize(key[:v.key], v.block, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := mustHex(t, v.ct)
+
+		got := make([]byte, v.block)
+		r.Encrypt(got, pt[:v.block])
+		if !bytes.Equal(got, want) {
+			t.Errorf("bloco %d, chave %d: cifrado %x, esperado %x", v.block*8, v.key*8, got, want)
+			continue
+		}
+		r.Decrypt(got, got)
+		if !bytes.Equal(got, pt[:v.block]) {
+			t.Errorf("bloco %d, chave %d: decifrado %x", v.block*8, v.key*8, got)
+		}
+	}
+}
+
+func TestRijndaelIgualAES(t *testing.T) {
+	rng := rand.New(rand.NewSource(13))
+	for _, keySize := range []int{16, 24, 32} {
+		key := make([]byte, keySize)
+		rng.Read(key)
+
+		var r cipher.Block
+		r, err := NewRijndael(key, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		a, _ := New(key)
+
+		for i := 0; i < 32; i++ {
+			src := make([]byte, BlockSize)
+			rng.Read(src)
+			got, want := make([]byte, BlockSize), make([]byte, BlockSize)
+			r.Encrypt(got, src)
+			a.Encrypt(want, src)
+			if !bytes.Equal(got, want) {
+				t.Fatalf("chave %d: Rijndael %x, AES %x", keySize*8, got, want)
+			}
+		}
+	}
+}
+
+func TestRijndaelTamanhoInvalido(t *testing.T) {
+	if _, err := NewRijndaelWithBlockSize(make([]byte, 18), 16, nil); err != (RijndaelSizeError{"chave", 18}) {
+		t.Errorf("chave de 18 bytes: err = %v", err)
+	}
+	if _, err := NewRijndaelWithBlockSize(make([]byte, 16), 36, nil); err != (RijndaelSizeError{"bloco", 36}) {
+		t.Errorf("bloco de 36 bytes: err = %v", err)
+	}
+}
@@ -1,8 +1,5 @@
 package main
 
-import (
-	"fmt"
-)
 var irreducible int = 0x11B
 
 func gfMul(a, b byte) byte {
@@ -21,25 +18,71 @@ func gfMul(a, b byte) byte {
 	return res
 }
 
+// polyDegree retorna o grau de um polinômio sobre GF(2) (-1 para o polinômio zero).
+func polyDegree(p int) int {
+	d := -1
+	for ; p != 0; p >>= 1 {
+		d++
+	}
+	return d
+}
+
+// polyDivMod divide a por b em GF(2)[x] (divisão longa com XOR no lugar da subtração).
+func polyDivMod(a, b int) (q, r int) {
+	r = a
+	db := polyDegree(b)
+	for polyDegree(r) >= db {
+		shift := polyDegree(r) - db
+		q ^= 1 << shift
+		r ^= b << shift
+	}
+	return q, r
+}
+
+// polyMulNoReduce multiplica em GF(2)[x] sem reduzir pelo polinômio irredutível.
+func polyMulNoReduce(a, b int) int {
+	res := 0
+	for ; b != 0; b >>= 1 {
+		if b&1 != 0 {
+			res ^= a
+		}
+		a <<= 1
+	}
+	return res
+}
+
+/*
+gfInv: Inverso multiplicativo em GF(2^8) pelo algoritmo de Euclides estendido.
+
+	O quociente e o resto precisam ser calculados com divisão de POLINÔMIOS
+	(polyDivMod), não com a divisão inteira "/" e "%": como inteiros,
+	0x11B / 0x53 = 3, mas como polinômios o quociente é x^2 + 1 (0x05) e o
+	resto é x^2 (0x04).
+
+	Os coeficientes t nunca passam do grau 7, então a multiplicação não
+	precisa de redução.
+*/
 func gfInv(a byte) byte {
+	return gfInvMod(a, irreducible)
+}
+
+// gfInvMod é gfInv com o polinômio redutor como parâmetro (ver aes_sboxgen.go).
+func gfInvMod(a byte, poly int) byte {
 	if a == 0 {
 		return 0
 	}
 
-	r0, r1 := irreducible, int(a)
+	r0, r1 := poly, int(a)
 	t0, t1 := 0, 1
 
 	for r1 != 0 {
-		q := r0 / r1
-		r0, r1 = r1, r0%r1
-
-		// Corrigir multiplicação: q é int, t1 é int, convertemos para byte
-		t0, t1 = t1, t0^int(gfMul(byte(q), byte(t1)))
+		q, r := polyDivMod(r0, r1)
+		r0, r1 = r1, r
+		t0, t1 = t1, t0^polyMulNoReduce(q, t1)
 	}
 	return byte(t0)
 }
 
-
 func affineTransform(x byte) byte {
 	var result byte = 0
 	for i := 0; i < 8; i++ {
@@ -54,25 +97,26 @@ func affineTransform(x byte) byte {
 	return result
 }
 
+// inverseAffineTransform: b_i = y_(i+2) ^ y_(i+5) ^ y_(i+7) ^ d_i, com d = 0x05.
 func inverseAffineTransform(y byte) byte {
 	var result byte = 0
 	for i := 0; i < 8; i++ {
 		bit := ((y >> ((i + 2) % 8)) & 1) ^
 			((y >> ((i + 5) % 8)) & 1) ^
 			((y >> ((i + 7) % 8)) & 1) ^
-			((y >> i) & 1) ^
 			((0x05 >> i) & 1)
 		result |= bit << i
 	}
 	return result
 }
 
-func sbox(x byte) byte {
+// sboxByte calcula S(x) = affineTransform(x^-1), sem consultar a tabela sbox de aes.go.
+func sboxByte(x byte) byte {
 	inv := gfInv(x)
 	return affineTransform(inv)
 }
 
-func invSbox(y byte) byte {
+func invSboxByte(y byte) byte {
 	pre := inverseAffineTransform(y)
 	return gfInv(pre)
 }
@@ -80,7 +124,7 @@ func invSbox(y byte) byte {
 func generateSbox() [256]byte {
 	var table [256]byte
 	for i := 0; i < 256; i++ {
-		table[i] = sbox(byte(i))
+		table[i] = sboxByte(byte(i))
 	}
 	return table
 }
@@ -88,24 +132,7 @@ func generateSbox() [256]byte {
 func generateInvSbox() [256]byte {
 	var table [256]byte
 	for i := 0; i < 256; i++ {
-		table[i] = invSbox(byte(i))
+		table[i] = invSboxByte(byte(i))
 	}
 	return table
 }
-
-func main() {
-	s := generateSbox()
-	i := generateInvSbox()
-
-	fmt.Printf("S-box[0x53] = 0x%02X\n", s[0x53]) // Esperado: 0xED
-	fmt.Printf("InvS-box[0xED] = 0x%02X\n", i[0xED]) // Esperado: 0x53
-
-	fmt.Println("Tabela S-box gerada:")
-	for i := 0; i < 256; i++ {
-		fmt.Printf("0x%02X,", s[i])
-		if (i+1)%16 == 0 {
-			fmt.Println()
-		}
-	}
-}
-
@@ -3,7 +3,7 @@ package main
 import "testing"
 
 func TestSboxCorrectness(t *testing.T) {
-	known := [256]byte{
+	known := []byte{
 		0x63, 0x7c, 0x77, 0x7b, 0xf2, 0x6b, 0x6f, 0xc5,
 		0x30, 0x01, 0x67, 0x2b, 0xfe, 0xd7, 0xab, 0x76,
 		0xca, 0x82, 0xc9, 0x7d, 0xfa, 0x59, 0x47, 0xf0,
@@ -28,3 +28,30 @@ func TestSboxInverse(t *testing.T) {
 		}
 	}
 }
+
+// A S-box calculada a partir de gfInv e affineTransform deve ser idêntica
+// à tabela usada por aes.go.
+func TestSboxIgualTabela(t *testing.T) {
+	s := generateSbox()
+	inv := generateInvSbox()
+	for i := 0; i < 256; i++ {
+		if s[i] != sbox[i] {
+			t.Fatalf("generateSbox[%02X] = %02X, tabela %02X", i, s[i], sbox[i])
+		}
+		if inv[i] != invSbox[i] {
+			t.Fatalf("generateInvSbox[%02X] = %02X, tabela %02X", i, inv[i], invSbox[i])
+		}
+	}
+
+	if s[0x53] != 0xED || inv[0xED] != 0x53 {
+		t.Fatalf("S-box[0x53] = %02X, InvS-box[0xED] = %02X", s[0x53], inv[0xED])
+	}
+}
+
+func TestGfInv(t *testing.T) {
+	for a := 1; a < 256; a++ {
+		if p := gfMul(byte(a), gfInv(byte(a))); p != 1 {
+			t.Fatalf("%02X * gfInv(%02X) = %02X", a, a, p)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+/*
+
+Gerador parametrizado de S-boxes no estilo Rijndael
+
+A S-box do AES é S(x) = A·x^-1 + c, com o inverso calculado em
+GF(2^8) = GF(2)[x]/(m(x)). O padrão fixa:
+
+	m(x) = x^8 + x^4 + x^3 + x + 1   (0x11B)
+	A    = matriz circulante da linha 0xF1
+	c    = 0x63
+
+Nenhuma dessas escolhas é mágica: existem 30 polinômios irredutíveis de
+grau 8 e qualquer matriz invertível gera uma permutação. Como x^-1 e
+A·y + c são bijeções, S também é, e as propriedades diferenciais e lineares
+(uniformidade 4, não linearidade 112) não mudam com A e c, porque
+equivalência afim preserva o espectro da DDT e da LAT. O que muda são
+pontos fixos, grau de cada bit e as relações algébricas exploráveis.
+
+Representação da matriz: Matrix[i] é a linha i; o bit j de Matrix[i] diz
+se x_j entra no bit de saída i.
+
+*/
+
+package main
+
+import (
+	"errors"
+	"math/bits"
+)
+
+var (
+	ErrReduciblePolynomial = errors.New("aes-sbox: polinômio redutível ou de grau diferente de 8")
+	ErrSingularMatrix      = errors.New("aes-sbox: matriz afim singular")
+)
+
+// SboxParams define uma S-box S(x) = Matrix·x^-1 + Constant em GF(2)[x]/(Poly).
+type SboxParams struct {
+	Poly     int
+	Matrix   [8]byte
+	Constant byte
+}
+
+// SboxPair guarda a S-box e sua inversa.
+type SboxPair struct {
+	Forward [256]byte
+	Inverse [256]byte
+}
+
+// RijndaelSboxParams devolve os parâmetros do padrão AES.
+func RijndaelSboxParams() SboxParams {
+	var p SboxParams
+	p.Poly = 0x11B
+	for i := range p.Matrix {
+		p.Matrix[i] = bits.RotateLeft8(0xF1, i)
+	}
+	p.Constant = 0x63
+	return p
+}
+
+// IsIrreducible diz se p tem grau 8 e nenhum divisor de grau 1 a 4.
+func IsIrreducible(p int) bool {
+	if polyDegree(p) != 8 {
+		return false
+	}
+	for q := 2; q < 1<<5; q++ {
+		if _, r := polyDivMod(p, q); r == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSingular diz se a matriz 8x8 sobre GF(2) não tem inversa (eliminação gaussiana).
+func IsSingular(m [8]byte) bool {
+	rows := m
+	for col := 0; col < 8; col++ {
+		pivot := -1
+		for r := col; r < 8; r++ {
+			if rows[r]>>col&1 == 1 {
+				pivot = r
+				break
+			}
+		}
+		if pivot < 0 {
+			return true
+		}
+		rows[col], rows[pivot] = rows[pivot], rows[col]
+		for r := 0; r < 8; r++ {
+			if r != col && rows[r]>>col&1 == 1 {
+				rows[r] ^= rows[col]
+			}
+		}
+	}
+	return false
+}
+
+// applyAffine calcula Matrix·x + Constant.
+func (p SboxParams) applyAffine(x byte) byte {
+	var y byte
+	for i, row := range p.Matrix {
+		y |= byte(bits.OnesCount8(row&x)&1) << i
+	}
+	return y ^ p.Constant
+}
+
+/*
+GenerateSbox: Constrói a S-box e a inversa a partir dos parâmetros.
+
+	A inversa é obtida invertendo a tabela: como polinômio irredutível e
+	matriz invertível garantem uma permutação, cada saída aparece uma vez.
+*/
+func GenerateSbox(p SboxParams) (*SboxPair, error) {
+	if !IsIrreducible(p.Poly) {
+		return nil, ErrReduciblePolynomial
+	}
+	if IsSingular(p.Matrix) {
+		return nil, ErrSingularMatrix
+	}
+
+	s := new(SboxPair)
+	for x := 0; x < 256; x++ {
+		y := p.applyAffine(gfInvMod(byte(x), p.Poly))
+		s.Forward[x] = y
+		s.Inverse[y] = byte(x)
+	}
+	return s, nil
+}
+
+// IrreduciblePolynomials lista os 30 polinômios irredutíveis de grau 8.
+func IrreduciblePolynomials() []int {
+	var polys []int
+	for p := 0x100; p < 0x200; p++ {
+		if IsIrreducible(p) {
+			polys = append(polys, p)
+		}
+	}
+	return polys
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/osdeving/sboxanalysis"
+)
+
+func TestGenerateSboxRijndael(t *testing.T) {
+	s, err := GenerateSbox(RijndaelSboxParams())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Forward != sbox {
+		t.Error("S-box gerada difere da tabela de aes.go")
+	}
+	if s.Inverse != invSbox {
+		t.Error("S-box inversa gerada difere da tabela de aes.go")
+	}
+}
+
+func TestIrreduciblePolynomials(t *testing.T) {
+	polys := IrreduciblePolynomials()
+	if len(polys) != 30 {
+		t.Fatalf("%d polinômios irredutíveis, esperado 30", len(polys))
+	}
+	if !IsIrreducible(0x11B) {
+		t.Error("0x11B deveria ser irredutível")
+	}
+
+	// x^8 + x^4 + x^3 + x = x·(x^7 + x^3 + x^2 + 1)
+	for _, p := range []int{0x11A, 0x1B, 0x100, 0x111} {
+		params := RijndaelSboxParams()
+		params.Poly = p
+		if _, err := GenerateSbox(params); err != ErrReduciblePolynomial {
+			t.Errorf("polinômio %#x: err = %v", p, err)
+		}
+	}
+}
+
+func TestGenerateSboxMatrizSingular(t *testing.T) {
+	params := RijndaelSboxParams()
+	params.Matrix[7] = params.Matrix[0] ^ params.Matrix[1]
+	if _, err := GenerateSbox(params); err != ErrSingularMatrix {
+		t.Errorf("err = %v, esperado ErrSingularMatrix", err)
+	}
+}
+
+func TestRijndaelSboxPadrao(t *testing.T) {
+	key := mustHex(t, "000102030405060708090a0b0c0d0e0f")
+	plaintext := mustHex(t, "00112233445566778899aabbccddeeff")
+	want := mustHex(t, "69c4e0d86a7b0430d8cdb78070b4c55a")
+
+	r, err := NewRijndael(key, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make([]byte, 16)
+	r.Encrypt(got, plaintext)
+	if !bytes.Equal(got, want) {
+		t.Fatalf("cifrado %x, esperado %x", got, want)
+	}
+	r.Decrypt(got, got)
+	if !bytes.Equal(got, plaintext) {
+		t.Fatalf("decifrado %x, esperado %x", got, plaintext)
+	}
+}
+
+func TestRijndaelSboxAlternativa(t *testing.T) {
+	// Polinômio x^8 + x^4 + x^3 + x^2 + 1, matriz identidade, sem constante
+	params := SboxParams{Poly: 0x11D, Constant: 0}
+	for i := range params.Matrix {
+		params.Matrix[i] = 1 << i
+	}
+	s, err := GenerateSbox(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key := mustHex(t, "000102030405060708090a0b0c0d0e0f1011121314151617")
+	plaintext := mustHex(t, "00112233445566778899aabbccddeeff")
+
+	r, err := NewRijndael(key, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, _ := New(key)
+
+	got := make([]byte, 16)
+	r.Encrypt(got, plaintext)
+	if bytes.Equal(got, a.EncryptBlock(plaintext)) {
+		t.Error("variante com outra S-box não deveria coincidir com o AES")
+	}
+	r.Decrypt(got, got)
+	if !bytes.Equal(got, plaintext) {
+		t.Fatalf("decifrado %x, esperado %x", got, plaintext)
+	}
+}
+
+func TestSboxGeradaPerfil(t *testing.T) {
+	// Trocar polinômio, matriz e constante preserva DDT/LAT do inverso: as
+	// propriedades diferenciais e lineares continuam 4 e 112.
+	identity := SboxParams{Poly: 0x11B}
+	for i := range identity.Matrix {
+		identity.Matrix[i] = 1 << i
+	}
+	other := RijndaelSboxParams()
+	other.Poly, other.Constant = 0x1F5, 0xA5
+
+	for _, params := range []SboxParams{RijndaelSboxParams(), identity, other} {
+		s, err := GenerateSbox(params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		profile := sboxanalysis.FromBytes(s.Forward)
+		if du := profile.DifferentialUniformity(); du != 4 {
+			t.Errorf("%+v: uniformidade diferencial %d", params, du)
+		}
+		if nl := profile.Nonlinearity(); nl != 112 {
+			t.Errorf("%+v: não linearidade %d", params, nl)
+		}
+	}
+
+	// Sem a transformação afim o inverso tem pontos fixos (0 e 1); a constante
+	// 0x63 do AES existe justamente para eliminá-los.
+	s, _ := GenerateSbox(identity)
+	if fixed := sboxanalysis.FromBytes(s.Forward).FixedPoints(); len(fixed) != 2 {
+		t.Errorf("pontos fixos de x^-1: %v", fixed)
+	}
+}
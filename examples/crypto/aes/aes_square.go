@@ -0,0 +1,154 @@
+/*
+
+Ataque square (integral) contra o AES-128 de 4 rodadas
+
+Criado pelos próprios autores do Rijndael junto com a cifra Square (Daemen,
+Knudsen e Rijmen, 1997), é o melhor ataque conhecido contra poucas rodadas.
+
+Λ-set: 256 textos claros que diferem apenas em um byte, que assume todos os
+valores 0x00..0xff; os outros 15 bytes são constantes.
+
+	byte "ativo" (A):   todos os 256 valores aparecem uma vez
+	byte constante (C): o mesmo valor nos 256 textos
+	byte balanceado:    o XOR dos 256 valores é zero
+
+Propagação pelas rodadas (SubBytes e AddRoundKey preservam A e C, ShiftRows
+só muda posições e MixColumns espalha):
+
+	entrada   1 byte A, 15 C
+	rodada 1  4 bytes A (uma coluna, após MixColumns)
+	rodada 2  16 bytes A
+	rodada 3  16 bytes balanceados (MixColumns de bytes A soma zero)
+	rodada 4  (final, sem MixColumns) nada garantido
+
+Ataque: para cada posição j do ciphertext e cada palpite k do byte j da
+chave da rodada 4, desfaz a última rodada naquele byte:
+
+	soma = XOR sobre os 256 ciphertexts de InvSbox[c[j] ^ k]
+
+Com o palpite certo a soma é zero (saída balanceada da rodada 3). Um palpite
+errado passa com probabilidade 1/256, então dois ou três Λ-sets eliminam
+todos os falsos positivos. Os 16 bytes recuperados formam a chave da rodada 4,
+e RecoverKey (aes_keyinv.go) volta até a chave original.
+
+Custo: algumas centenas de textos escolhidos e 16*256*256 consultas à
+InvSbox por Λ-set.
+
+*/
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+)
+
+// squareMaxLambdaSets limita quantos Λ-sets são tentados antes de desistir.
+const squareMaxLambdaSets = 16
+
+// SquareResult reúne o resultado do ataque square.
+type SquareResult struct {
+	LastRoundKey []byte // chave da rodada 4 (16 bytes)
+	Key          []byte // chave original do AES-128
+	LambdaSets   int    // quantos Λ-sets (de 256 textos) foram necessários
+}
+
+/*
+SquareAttack: Recupera a chave de um AES-128 de 4 rodadas a partir de um
+oráculo de cifragem (ataque de texto claro escolhido).
+
+	encrypt cifra um bloco de 16 bytes com a chave desconhecida, por exemplo
+	o método Encrypt de NewWithRounds(chave, 4).
+*/
+func SquareAttack(encrypt func(dst, src []byte)) (*SquareResult, error) {
+	// candidates[j][k] == true enquanto k ainda é possível para o byte j
+	var candidates [BlockSize][256]bool
+	for j := range candidates {
+		for k := range candidates[j] {
+			candidates[j][k] = true
+		}
+	}
+
+	ciphertexts := make([][BlockSize]byte, 256)
+	for set := 1; set <= squareMaxLambdaSets; set++ {
+		lambdaSet(set, ciphertexts, encrypt)
+
+		for j := 0; j < BlockSize; j++ {
+			for k := 0; k < 256; k++ {
+				if candidates[j][k] && !balancedAfterLastRound(ciphertexts, j, byte(k)) {
+					candidates[j][k] = false
+				}
+			}
+		}
+
+		lastRoundKey, ok := uniqueCandidates(&candidates)
+		if !ok {
+			continue
+		}
+
+		key, err := RecoverKey(lastRoundKey, 4)
+		if err != nil {
+			return nil, err
+		}
+		if !confirmKey(key, encrypt) {
+			return nil, errors.New("aes: chave recuperada não confere com o oráculo")
+		}
+		return &SquareResult{LastRoundKey: lastRoundKey, Key: key, LambdaSets: set}, nil
+	}
+	return nil, errors.New("aes: ataque square não convergiu (o oráculo tem 4 rodadas?)")
+}
+
+// lambdaSet cifra os 256 textos de um Λ-set: byte 0 ativo, os demais com
+// constantes pseudoaleatórias que mudam a cada set.
+func lambdaSet(set int, out [][BlockSize]byte, encrypt func(dst, src []byte)) {
+	var plaintext [BlockSize]byte
+	rand.New(rand.NewSource(int64(set))).Read(plaintext[:])
+
+	for v := 0; v < 256; v++ {
+		plaintext[0] = byte(v)
+		encrypt(out[v][:], plaintext[:])
+	}
+}
+
+// balancedAfterLastRound testa o palpite k para o byte j da última chave.
+// ShiftRows não altera valores, só posições, e por isso pode ser ignorado.
+func balancedAfterLastRound(ciphertexts [][BlockSize]byte, j int, k byte) bool {
+	var sum byte
+	for i := range ciphertexts {
+		sum ^= invSbox[ciphertexts[i][j]^k]
+	}
+	return sum == 0
+}
+
+func uniqueCandidates(candidates *[BlockSize][256]bool) ([]byte, bool) {
+	key := make([]byte, BlockSize)
+	for j := range candidates {
+		count := 0
+		for k, ok := range candidates[j] {
+			if ok {
+				key[j] = byte(k)
+				count++
+			}
+		}
+		if count != 1 {
+			return nil, false
+		}
+	}
+	return key, true
+}
+
+// confirmKey compara um bloco cifrado pelo oráculo com a chave candidata.
+func confirmKey(key []byte, encrypt func(dst, src []byte)) bool {
+	a, err := NewWithRounds(key, 4)
+	if err != nil {
+		return false
+	}
+
+	plaintext := []byte("confirma a chave")
+	want := make([]byte, BlockSize)
+	got := make([]byte, BlockSize)
+	encrypt(want, plaintext)
+	a.Encrypt(got, plaintext)
+	return bytes.Equal(got, want)
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestNewWithRounds(t *testing.T) {
+	key := mustHex(t, "000102030405060708090a0b0c0d0e0f")
+	plaintext := mustHex(t, "00112233445566778899aabbccddeeff")
+
+	full, _ := NewWithRounds(key, 10)
+	if got := full.EncryptBlock(plaintext); !bytes.Equal(got, mustHex(t, "69c4e0d86a7b0430d8cdb78070b4c55a")) {
+		t.Fatalf("10 rodadas: %x", got)
+	}
+
+	for rounds := 1; rounds <= 10; rounds++ {
+		a, err := NewWithRounds(key, rounds)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a.Nr != rounds {
+			t.Fatalf("Nr = %d, esperado %d", a.Nr, rounds)
+		}
+		if dec := a.DecryptBlock(a.EncryptBlock(plaintext)); !bytes.Equal(dec, plaintext) {
+			t.Fatalf("%d rodadas: decifragem falhou", rounds)
+		}
+	}
+
+	for _, rounds := range []int{0, 11} {
+		if _, err := NewWithRounds(key, rounds); err == nil {
+			t.Errorf("%d rodadas deveria ser rejeitado", rounds)
+		}
+	}
+}
+
+// Propriedade usada pelo ataque: após 3 rodadas completas todo byte de um
+// Λ-set é balanceado (XOR dos 256 valores = 0).
+func TestLambdaSetBalanceado(t *testing.T) {
+	key := make([]byte, 16)
+	rand.New(rand.NewSource(3)).Read(key)
+	a, _ := New(key)
+	w := a.expandedKey
+
+	var sum [BlockSize]byte
+	plaintext := make([]byte, BlockSize)
+	for v := 0; v < 256; v++ {
+		plaintext[5] = byte(v)
+		var state State
+		for i := 0; i < 16; i++ {
+			state[i%4][i/4] = plaintext[i]
+		}
+		AddRoundKey(&state, flattenKey(w[0:4]))
+		for round := 1; round <= 3; round++ {
+			SubBytes(&state)
+			ShiftRows(&state)
+			MixColumns(&state)
+			AddRoundKey(&state, flattenKey(w[round*4:(round+1)*4]))
+		}
+		for i, b := range state.Bytes() {
+			sum[i] ^= b
+		}
+	}
+
+	if sum != [BlockSize]byte{} {
+		t.Fatalf("soma após 3 rodadas não é zero: %x", sum)
+	}
+}
+
+func TestSquareAttack4Rodadas(t *testing.T) {
+	rng := rand.New(rand.NewSource(9))
+	key := make([]byte, 16)
+	rng.Read(key)
+
+	oracle, err := NewWithRounds(key, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	res, err := SquareAttack(oracle.Encrypt)
+	if err != nil {
+		t.Fatalf("chave %x: %v", key, err)
+	}
+	if !bytes.Equal(res.Key, key) {
+		t.Fatalf("chave recuperada %x, esperado %x", res.Key, key)
+	}
+	t.Logf("chave %x recuperada com %d Λ-sets (%d textos escolhidos) em %v",
+		res.Key, res.LambdaSets, 256*res.LambdaSets, time.Since(start))
+}
+
+func TestSquareAttackFalhaCom5Rodadas(t *testing.T) {
+	oracle, _ := NewWithRounds(make([]byte, 16), 5)
+	if _, err := SquareAttack(oracle.Encrypt); err == nil {
+		t.Fatal("o ataque de 4 rodadas não deveria funcionar contra 5 rodadas")
+	}
+}
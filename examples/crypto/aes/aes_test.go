@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"encoding/hex"
 	"testing"
 )
 
@@ -39,3 +40,79 @@ func TestAESCifraPadrao(t *testing.T) {
 		t.Errorf("Falha na decriptação: obtido %x, esperado %x", dec, plaintext)
 	}
 }
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// Vetores do FIPS-197, apêndice C (C.1, C.2 e C.3)
+func TestAESApendiceC(t *testing.T) {
+	casos := []struct {
+		nome       string
+		key        string
+		nk, nr     int
+		ciphertext string
+	}{
+		{"AES-128", "000102030405060708090a0b0c0d0e0f", 4, 10, "69c4e0d86a7b0430d8cdb78070b4c55a"},
+		{"AES-192", "000102030405060708090a0b0c0d0e0f1011121314151617", 6, 12, "dda97ca4864cdfe06eaf70a0ec0d7191"},
+		{"AES-256", "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f", 8, 14, "8ea2b7ca516745bfeafc49904b496089"},
+	}
+
+	plaintext := mustHex(t, "00112233445566778899aabbccddeeff")
+
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			a, err := New(mustHex(t, c.key))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if a.Nk != c.nk || a.Nr != c.nr {
+				t.Errorf("Nk/Nr: obtido %d/%d, esperado %d/%d", a.Nk, a.Nr, c.nk, c.nr)
+			}
+
+			expected := mustHex(t, c.ciphertext)
+			ciphertext := a.EncryptBlock(plaintext)
+			if !bytes.Equal(ciphertext, expected) {
+				t.Errorf("Falha no %s: obtido %x, esperado %x", c.nome, ciphertext, expected)
+			}
+
+			dec := a.DecryptBlock(ciphertext)
+			if !bytes.Equal(dec, plaintext) {
+				t.Errorf("Falha na decriptação: obtido %x, esperado %x", dec, plaintext)
+			}
+		})
+	}
+}
+
+// Última palavra da chave expandida (FIPS-197, apêndice A)
+func TestKeyExpansionApendiceA(t *testing.T) {
+	casos := []struct {
+		key  string
+		last string
+	}{
+		{"2b7e151628aed2a6abf7158809cf4f3c", "b6630ca6"},
+		{"8e73b0f7da0e6452c810f32b809079e562f8ead2522c6b7b", "01002202"},
+		{"603deb1015ca71be2b73aef0857d77811f352c073b6108d72d9810a30914dff4", "706c631e"},
+	}
+
+	for _, c := range casos {
+		w := KeyExpansion(mustHex(t, c.key))
+		last := w[len(w)-1]
+		if !bytes.Equal(last[:], mustHex(t, c.last)) {
+			t.Errorf("chave %s: última palavra %x, esperado %s", c.key, last, c.last)
+		}
+	}
+}
+
+func TestNewTamanhoInvalido(t *testing.T) {
+	for _, n := range []int{0, 8, 15, 17, 20, 33} {
+		if _, err := New(make([]byte, n)); err == nil {
+			t.Errorf("New com chave de %d bytes deveria falhar", n)
+		}
+	}
+}
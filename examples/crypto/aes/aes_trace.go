@@ -0,0 +1,165 @@
+/*
+
+Rastreamento (trace) rodada a rodada do AES
+
+	rec := &TraceRecorder{}
+	EncryptBlockTrace(plaintext, KeyExpansion(key), rec)
+
+	rec.WriteText(os.Stdout) // matrizes 4x4 lado a lado, como no FIPS-197 apêndice B
+	out, _ := rec.JSON()     // mesmas etapas em JSON, para ferramentas externas
+
+*/
+
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// TraceRecorder é um Tracer que apenas acumula as etapas em ordem.
+type TraceRecorder struct {
+	Steps []TraceStep
+}
+
+func (r *TraceRecorder) Trace(step TraceStep) {
+	r.Steps = append(r.Steps, step)
+}
+
+// EncryptBlockTrace cifra um bloco com a chave da instância reportando cada etapa.
+func (a *AES) EncryptBlockTrace(input []byte, tracer Tracer) []byte {
+	return EncryptBlockTrace(input, a.expandedKey, tracer)
+}
+
+// DecryptBlockTrace decifra um bloco com a chave da instância reportando cada etapa.
+func (a *AES) DecryptBlockTrace(input []byte, tracer Tracer) []byte {
+	return DecryptBlockTrace(input, a.expandedKey, tracer)
+}
+
+// Bytes devolve o state na ordem de entrada/saída (coluna a coluna).
+func (s State) Bytes() []byte {
+	out := make([]byte, 16)
+	for i := 0; i < 16; i++ {
+		out[i] = s[i%4][i/4]
+	}
+	return out
+}
+
+// Rows devolve as 4 linhas do state em hexadecimal ("19 a0 9a e9").
+func (s State) Rows() [4]string {
+	var rows [4]string
+	for row := 0; row < 4; row++ {
+		rows[row] = fmt.Sprintf("%02x %02x %02x %02x", s[row][0], s[row][1], s[row][2], s[row][3])
+	}
+	return rows
+}
+
+// keyState organiza uma chave de rodada (16 bytes) no mesmo formato do state.
+func keyState(roundKey []byte) State {
+	var s State
+	for i := 0; i < 16; i++ {
+		s[i%4][i/4] = roundKey[i]
+	}
+	return s
+}
+
+type traceStepJSON struct {
+	Round    int       `json:"round"`
+	Step     string    `json:"step"`
+	State    string    `json:"state"`
+	Matrix   [4]string `json:"matrix"`
+	RoundKey string    `json:"roundKey,omitempty"`
+}
+
+// MarshalJSON representa o state como hex (ordem dos bytes) e como matriz 4x4.
+func (t TraceStep) MarshalJSON() ([]byte, error) {
+	return json.Marshal(traceStepJSON{
+		Round:    t.Round,
+		Step:     t.Step,
+		State:    hex.EncodeToString(t.State.Bytes()),
+		Matrix:   t.State.Rows(),
+		RoundKey: hex.EncodeToString(t.RoundKey),
+	})
+}
+
+// JSON devolve todas as etapas registradas como um array JSON indentado.
+func (r *TraceRecorder) JSON() ([]byte, error) {
+	return json.MarshalIndent(r.Steps, "", "  ")
+}
+
+var traceLabels = map[string]string{
+	StepInput:         "Entrada",
+	StepStart:         "Início da rodada",
+	StepSubBytes:      "Após SubBytes",
+	StepShiftRows:     "Após ShiftRows",
+	StepMixColumns:    "Após MixColumns",
+	StepAddRoundKey:   "Após AddRoundKey",
+	StepInvSubBytes:   "Após InvSubBytes",
+	StepInvShiftRows:  "Após InvShiftRows",
+	StepInvMixColumns: "Após InvMixColumns",
+	StepOutput:        "Saída",
+}
+
+const traceColumnWidth = 20
+
+/*
+WriteText: Escreve o trace agrupado por rodada, com as matrizes 4x4 lado a
+lado e alinhadas. Nas etapas AddRoundKey a chave da rodada aparece em uma
+coluna própria, antes do resultado:
+
+	Rodada 1
+	Início da rodada    Após SubBytes       ...  Chave da rodada     Após AddRoundKey
+	19 a0 9a e9         d4 e0 b8 1e         ...  a0 88 23 2a         a4 68 6b 02
+	3d f4 c6 f8         27 bf b4 41         ...  fa 54 a3 6c         9c 9f 5b 6a
+	...
+*/
+func (r *TraceRecorder) WriteText(w io.Writer) error {
+	var sb strings.Builder
+
+	for i := 0; i < len(r.Steps); {
+		round := r.Steps[i].Round
+		var labels []string
+		var matrices [][4]string
+
+		for ; i < len(r.Steps) && r.Steps[i].Round == round; i++ {
+			step := r.Steps[i]
+			if step.RoundKey != nil {
+				labels = append(labels, "Chave da rodada")
+				matrices = append(matrices, keyState(step.RoundKey).Rows())
+			}
+			label, ok := traceLabels[step.Step]
+			if !ok {
+				label = step.Step
+			}
+			labels = append(labels, label)
+			matrices = append(matrices, step.State.Rows())
+		}
+
+		fmt.Fprintf(&sb, "Rodada %d\n", round)
+		writeColumns(&sb, labels)
+		for row := 0; row < 4; row++ {
+			cells := make([]string, len(matrices))
+			for j, m := range matrices {
+				cells[j] = m[row]
+			}
+			writeColumns(&sb, cells)
+		}
+		sb.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// writeColumns escreve uma linha com as células alinhadas em colunas fixas.
+func writeColumns(sb *strings.Builder, cells []string) {
+	var line strings.Builder
+	for _, c := range cells {
+		fmt.Fprintf(&line, "%-*s", traceColumnWidth, c)
+	}
+	sb.WriteString(strings.TrimRight(line.String(), " "))
+	sb.WriteString("\n")
+}
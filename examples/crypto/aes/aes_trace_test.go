@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// Valores da rodada 1 do FIPS-197, apêndice B (estado em ordem de bytes).
+func TestTraceApendiceB(t *testing.T) {
+	key := mustHex(t, "2b7e151628aed2a6abf7158809cf4f3c")
+	input := mustHex(t, "3243f6a8885a308d313198a2e0370734")
+
+	rec := &TraceRecorder{}
+	out := EncryptBlockTrace(input, KeyExpansion(key), rec)
+	if !bytes.Equal(out, mustHex(t, "3925841d02dc09fbdc118597196a0b32")) {
+		t.Fatalf("saída com trace: %x", out)
+	}
+
+	esperado := []struct {
+		round int
+		step  string
+		state string
+	}{
+		{1, StepStart, "193de3bea0f4e22b9ac68d2ae9f84808"},
+		{1, StepSubBytes, "d42711aee0bf98f1b8b45de51e415230"},
+		{1, StepShiftRows, "d4bf5d30e0b452aeb84111f11e2798e5"},
+		{1, StepMixColumns, "046681e5e0cb199a48f8d37a2806264c"},
+		{10, StepStart, "eb40f21e592e38848ba113e71bc342d2"},
+		{10, StepOutput, "3925841d02dc09fbdc118597196a0b32"},
+	}
+	for _, e := range esperado {
+		step, ok := findStep(rec.Steps, e.round, e.step)
+		if !ok {
+			t.Fatalf("etapa %s da rodada %d não registrada", e.step, e.round)
+		}
+		if got := hex.EncodeToString(step.State.Bytes()); got != e.state {
+			t.Errorf("rodada %d %s: obtido %s, esperado %s", e.round, e.step, got, e.state)
+		}
+	}
+
+	ark, _ := findStep(rec.Steps, 1, StepAddRoundKey)
+	if got := hex.EncodeToString(ark.RoundKey); got != "a0fafe1788542cb123a339392a6c7605" {
+		t.Errorf("chave da rodada 1: %s", got)
+	}
+
+	// 1 entrada + 2 etapas da rodada 0 + 9*5 rodadas completas + 4 da final + saída
+	if n := len(rec.Steps); n != 1+1+9*5+4+1 {
+		t.Errorf("número de etapas: %d", n)
+	}
+}
+
+func TestTraceDecifragem(t *testing.T) {
+	key := mustHex(t, "000102030405060708090a0b0c0d0e0f1011121314151617")
+	ciphertext := mustHex(t, "dda97ca4864cdfe06eaf70a0ec0d7191")
+
+	a, _ := New(key)
+	rec := &TraceRecorder{}
+	out := a.DecryptBlockTrace(ciphertext, rec)
+	if !bytes.Equal(out, mustHex(t, "00112233445566778899aabbccddeeff")) {
+		t.Fatalf("decifragem com trace: %x", out)
+	}
+
+	// FIPS-197 C.2, round[12].istart: início da última rodada inversa (nossa rodada 0)
+	step, _ := findStep(rec.Steps, 0, StepStart)
+	if got := hex.EncodeToString(step.State.Bytes()); got != "6353e08c0960e104cd70b751bacad0e7" {
+		t.Errorf("istart da rodada 0: %s", got)
+	}
+	if first, last := rec.Steps[0], rec.Steps[len(rec.Steps)-1]; first.Round != 12 || last.Round != 0 {
+		t.Errorf("rodadas em ordem inesperada: %d..%d", first.Round, last.Round)
+	}
+}
+
+func TestTraceJSON(t *testing.T) {
+	rec := &TraceRecorder{}
+	EncryptBlockTrace(mustHex(t, "3243f6a8885a308d313198a2e0370734"),
+		KeyExpansion(mustHex(t, "2b7e151628aed2a6abf7158809cf4f3c")), rec)
+
+	out, err := rec.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var steps []struct {
+		Round    int       `json:"round"`
+		Step     string    `json:"step"`
+		State    string    `json:"state"`
+		Matrix   [4]string `json:"matrix"`
+		RoundKey string    `json:"roundKey"`
+	}
+	if err := json.Unmarshal(out, &steps); err != nil {
+		t.Fatal(err)
+	}
+	if len(steps) != len(rec.Steps) {
+		t.Fatalf("JSON com %d etapas, esperado %d", len(steps), len(rec.Steps))
+	}
+
+	first := steps[0]
+	if first.Step != StepInput || first.State != "3243f6a8885a308d313198a2e0370734" {
+		t.Errorf("primeira etapa: %+v", first)
+	}
+	if first.Matrix[0] != "32 88 31 e0" {
+		t.Errorf("primeira linha da matriz: %q", first.Matrix[0])
+	}
+	if steps[1].RoundKey != "2b7e151628aed2a6abf7158809cf4f3c" {
+		t.Errorf("chave da rodada 0: %q", steps[1].RoundKey)
+	}
+}
+
+func TestTraceTexto(t *testing.T) {
+	rec := &TraceRecorder{}
+	EncryptBlockTrace(mustHex(t, "3243f6a8885a308d313198a2e0370734"),
+		KeyExpansion(mustHex(t, "2b7e151628aed2a6abf7158809cf4f3c")), rec)
+
+	var sb strings.Builder
+	if err := rec.WriteText(&sb); err != nil {
+		t.Fatal(err)
+	}
+	text := sb.String()
+
+	// Primeira linha da rodada 1: início, SubBytes, ShiftRows, MixColumns, chave, resultado
+	linha := "19 a0 9a e9         d4 e0 b8 1e         d4 e0 b8 1e         04 e0 48 28         a0 88 23 2a         a4 68 6b 02"
+	if !strings.Contains(text, "Rodada 1\nInício da rodada") || !strings.Contains(text, linha+"\n") {
+		t.Errorf("texto da rodada 1 inesperado:\n%s", text)
+	}
+	for _, l := range strings.Split(text, "\n") {
+		if strings.HasSuffix(l, " ") {
+			t.Fatalf("linha com espaço no final: %q", l)
+		}
+	}
+}
+
+func findStep(steps []TraceStep, round int, name string) (TraceStep, bool) {
+	for _, s := range steps {
+		if s.Round == round && s.Step == name {
+			return s, true
+		}
+	}
+	return TraceStep{}, false
+}
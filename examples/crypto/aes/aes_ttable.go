@@ -0,0 +1,186 @@
+/*
+
+AES com T-tables (Te0..Te3 / Td0..Td3)
+
+Na implementação de referência (aes.go) cada rodada faz SubBytes, ShiftRows,
+MixColumns e AddRoundKey separadamente, byte a byte. Como todas essas etapas
+são lineares exceto a S-box, dá para pré-calcular, para cada byte de entrada,
+a contribuição dele para uma coluna inteira após SubBytes + MixColumns:
+
+	Te0[x] = [02·S(x), 01·S(x), 01·S(x), 03·S(x)]   (uma palavra de 32 bits)
+	Te1[x] = Te0[x] rotacionado 8 bits para a direita
+	Te2[x] = Te0[x] rotacionado 16 bits
+	Te3[x] = Te0[x] rotacionado 24 bits
+
+Uma rodada inteira vira 16 consultas às tabelas e XORs de palavras de 32 bits:
+
+	t0 = Te0[s0>>24] ^ Te1[s1>>16] ^ Te2[s2>>8] ^ Te3[s3] ^ rk[0]
+
+(o ShiftRows está embutido na escolha de qual coluna alimenta cada byte).
+
+A decifragem usa a "cifra inversa equivalente" (FIPS-197, seção 5.3.5): as
+mesmas tabelas com InvSbox e coeficientes 0e, 09, 0d, 0b, e as chaves das
+rodadas intermediárias passam por InvMixColumns.
+
+Atenção: consultas a tabelas indexadas por dados secretos vazam informação
+pelo cache (ataques de temporização). Veja aes_bitsliced.go para uma
+alternativa em tempo constante.
+
+*/
+
+package main
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+	"math/bits"
+)
+
+var te0, te1, te2, te3 [256]uint32
+var td0, td1, td2, td3 [256]uint32
+
+func init() {
+	for x := 0; x < 256; x++ {
+		s := sbox[x]
+		w := uint32(gmul(s, 0x02))<<24 | uint32(s)<<16 | uint32(s)<<8 | uint32(gmul(s, 0x03))
+		te0[x] = w
+		te1[x] = bits.RotateLeft32(w, -8)
+		te2[x] = bits.RotateLeft32(w, -16)
+		te3[x] = bits.RotateLeft32(w, -24)
+
+		si := invSbox[x]
+		w = uint32(gmul(si, 0x0e))<<24 | uint32(gmul(si, 0x09))<<16 | uint32(gmul(si, 0x0d))<<8 | uint32(gmul(si, 0x0b))
+		td0[x] = w
+		td1[x] = bits.RotateLeft32(w, -8)
+		td2[x] = bits.RotateLeft32(w, -16)
+		td3[x] = bits.RotateLeft32(w, -24)
+	}
+}
+
+// gmul multiplica em GF(2^8) usando apenas xtime (multiplicação por x).
+func gmul(a, b byte) byte {
+	var res byte
+	for b > 0 {
+		if b&1 != 0 {
+			res ^= a
+		}
+		a = xtime(a)
+		b >>= 1
+	}
+	return res
+}
+
+// TTable é uma instância do AES com as chaves das rodadas em palavras de 32 bits.
+type TTable struct {
+	nr  int
+	enc []uint32
+	dec []uint32
+}
+
+// NewTTable cria a versão com T-tables a partir de uma chave de 16, 24 ou 32 bytes.
+func NewTTable(key []byte) (*TTable, error) {
+	a, err := New(key)
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(a.expandedKey)
+	t := &TTable{
+		nr:  a.Nr,
+		enc: make([]uint32, n),
+		dec: make([]uint32, n),
+	}
+	for i, w := range a.expandedKey {
+		t.enc[i] = binary.BigEndian.Uint32(w[:])
+	}
+
+	// Chaves da cifra inversa equivalente: ordem das rodadas invertida e
+	// InvMixColumns nas rodadas 1..Nr-1.
+	for round := 0; round <= t.nr; round++ {
+		for c := 0; c < Nb; c++ {
+			w := t.enc[(t.nr-round)*Nb+c]
+			if round > 0 && round < t.nr {
+				w = td0[sbox[w>>24]] ^ td1[sbox[w>>16&0xff]] ^ td2[sbox[w>>8&0xff]] ^ td3[sbox[w&0xff]]
+			}
+			t.dec[round*Nb+c] = w
+		}
+	}
+	return t, nil
+}
+
+func (t *TTable) BlockSize() int {
+	return BlockSize
+}
+
+func (t *TTable) Encrypt(dst, src []byte) {
+	checkBlocks(dst, src)
+	rk := t.enc
+
+	s0 := binary.BigEndian.Uint32(src[0:4]) ^ rk[0]
+	s1 := binary.BigEndian.Uint32(src[4:8]) ^ rk[1]
+	s2 := binary.BigEndian.Uint32(src[8:12]) ^ rk[2]
+	s3 := binary.BigEndian.Uint32(src[12:16]) ^ rk[3]
+
+	k := 4
+	for round := 1; round < t.nr; round++ {
+		t0 := te0[s0>>24] ^ te1[s1>>16&0xff] ^ te2[s2>>8&0xff] ^ te3[s3&0xff] ^ rk[k]
+		t1 := te0[s1>>24] ^ te1[s2>>16&0xff] ^ te2[s3>>8&0xff] ^ te3[s0&0xff] ^ rk[k+1]
+		t2 := te0[s2>>24] ^ te1[s3>>16&0xff] ^ te2[s0>>8&0xff] ^ te3[s1&0xff] ^ rk[k+2]
+		t3 := te0[s3>>24] ^ te1[s0>>16&0xff] ^ te2[s1>>8&0xff] ^ te3[s2&0xff] ^ rk[k+3]
+		s0, s1, s2, s3 = t0, t1, t2, t3
+		k += 4
+	}
+
+	// Rodada final: SubBytes + ShiftRows + AddRoundKey, sem MixColumns
+	t0 := subShift(s0, s1, s2, s3, &sbox) ^ rk[k]
+	t1 := subShift(s1, s2, s3, s0, &sbox) ^ rk[k+1]
+	t2 := subShift(s2, s3, s0, s1, &sbox) ^ rk[k+2]
+	t3 := subShift(s3, s0, s1, s2, &sbox) ^ rk[k+3]
+
+	binary.BigEndian.PutUint32(dst[0:4], t0)
+	binary.BigEndian.PutUint32(dst[4:8], t1)
+	binary.BigEndian.PutUint32(dst[8:12], t2)
+	binary.BigEndian.PutUint32(dst[12:16], t3)
+}
+
+func (t *TTable) Decrypt(dst, src []byte) {
+	checkBlocks(dst, src)
+	rk := t.dec
+
+	s0 := binary.BigEndian.Uint32(src[0:4]) ^ rk[0]
+	s1 := binary.BigEndian.Uint32(src[4:8]) ^ rk[1]
+	s2 := binary.BigEndian.Uint32(src[8:12]) ^ rk[2]
+	s3 := binary.BigEndian.Uint32(src[12:16]) ^ rk[3]
+
+	// InvShiftRows desloca para a direita: a coluna c recebe da coluna c-1, c-2, c-3
+	k := 4
+	for round := 1; round < t.nr; round++ {
+		t0 := td0[s0>>24] ^ td1[s3>>16&0xff] ^ td2[s2>>8&0xff] ^ td3[s1&0xff] ^ rk[k]
+		t1 := td0[s1>>24] ^ td1[s0>>16&0xff] ^ td2[s3>>8&0xff] ^ td3[s2&0xff] ^ rk[k+1]
+		t2 := td0[s2>>24] ^ td1[s1>>16&0xff] ^ td2[s0>>8&0xff] ^ td3[s3&0xff] ^ rk[k+2]
+		t3 := td0[s3>>24] ^ td1[s2>>16&0xff] ^ td2[s1>>8&0xff] ^ td3[s0&0xff] ^ rk[k+3]
+		s0, s1, s2, s3 = t0, t1, t2, t3
+		k += 4
+	}
+
+	t0 := subShift(s0, s3, s2, s1, &invSbox) ^ rk[k]
+	t1 := subShift(s1, s0, s3, s2, &invSbox) ^ rk[k+1]
+	t2 := subShift(s2, s1, s0, s3, &invSbox) ^ rk[k+2]
+	t3 := subShift(s3, s2, s1, s0, &invSbox) ^ rk[k+3]
+
+	binary.BigEndian.PutUint32(dst[0:4], t0)
+	binary.BigEndian.PutUint32(dst[4:8], t1)
+	binary.BigEndian.PutUint32(dst[8:12], t2)
+	binary.BigEndian.PutUint32(dst[12:16], t3)
+}
+
+// subShift monta uma coluna da rodada final: byte 0 de a, 1 de b, 2 de c e 3
+// de d (o deslocamento das linhas), cada um passando pela S-box.
+func subShift(a, b, c, d uint32, box *[256]byte) uint32 {
+	return uint32(box[a>>24])<<24 |
+		uint32(box[b>>16&0xff])<<16 |
+		uint32(box[c>>8&0xff])<<8 |
+		uint32(box[d&0xff])
+}
+
+var _ cipher.Block = (*TTable)(nil)
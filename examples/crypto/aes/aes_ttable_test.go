@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"crypto/aes"
+	"math/rand"
+	"testing"
+)
+
+func TestTTableTabelas(t *testing.T) {
+	// Valores conhecidos (FIPS-197 / implementação de referência de Rijndael)
+	if te0[0x00] != 0xc66363a5 || te0[0x01] != 0xf87c7c84 {
+		t.Errorf("Te0: %08x %08x", te0[0x00], te0[0x01])
+	}
+	if td0[0x00] != 0x51f4a750 || td0[0x01] != 0x7e416553 {
+		t.Errorf("Td0: %08x %08x", td0[0x00], td0[0x01])
+	}
+	for x := 0; x < 256; x++ {
+		if gmul(byte(x), 0x02) != xtime(byte(x)) || gmul(byte(x), 0x03) != mul(byte(x), 0x03) {
+			t.Fatalf("gmul(%02x) difere de xtime/mul", x)
+		}
+	}
+}
+
+// A versão com T-tables deve produzir exatamente a mesma saída da referência.
+func TestTTableIgualReferencia(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	for _, keySize := range []int{16, 24, 32} {
+		for i := 0; i < 200; i++ {
+			key := make([]byte, keySize)
+			src := make([]byte, BlockSize)
+			rng.Read(key)
+			rng.Read(src)
+
+			ref, _ := New(key)
+			fast, err := NewTTable(key)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := make([]byte, BlockSize)
+			fast.Encrypt(got, src)
+			if want := ref.EncryptBlock(src); !bytes.Equal(got, want) {
+				t.Fatalf("Encrypt chave=%x bloco=%x: obtido %x, esperado %x", key, src, got, want)
+			}
+
+			fast.Decrypt(got, src)
+			if want := ref.DecryptBlock(src); !bytes.Equal(got, want) {
+				t.Fatalf("Decrypt chave=%x bloco=%x: obtido %x, esperado %x", key, src, got, want)
+			}
+		}
+	}
+}
+
+func TestTTableSemAlocacao(t *testing.T) {
+	fast, _ := NewTTable(make([]byte, 16))
+	buf := make([]byte, BlockSize)
+	if n := testing.AllocsPerRun(100, func() { fast.Encrypt(buf, buf) }); n != 0 {
+		t.Errorf("Encrypt alocou %.0f vezes por bloco", n)
+	}
+}
+
+/*
+Comparação de desempenho (cifragem de um bloco):
+
+	go test -bench Encrypt .
+*/
+func benchmarkEncrypt(b *testing.B, encrypt func(dst, src []byte)) {
+	buf := make([]byte, BlockSize)
+	b.SetBytes(BlockSize)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		encrypt(buf, buf)
+	}
+}
+
+func BenchmarkEncryptReferencia(b *testing.B) {
+	a, _ := New(make([]byte, 16))
+	benchmarkEncrypt(b, a.Encrypt)
+}
+
+func BenchmarkEncryptTTable(b *testing.B) {
+	t, _ := NewTTable(make([]byte, 16))
+	benchmarkEncrypt(b, t.Encrypt)
+}
+
+func BenchmarkEncryptCryptoAES(b *testing.B) {
+	c, _ := aes.NewCipher(make([]byte, 16))
+	benchmarkEncrypt(b, c.Encrypt)
+}
+
+func BenchmarkDecryptReferencia(b *testing.B) {
+	a, _ := New(make([]byte, 16))
+	benchmarkEncrypt(b, a.Decrypt)
+}
+
+func BenchmarkDecryptTTable(b *testing.B) {
+	t, _ := NewTTable(make([]byte, 16))
+	benchmarkEncrypt(b, t.Decrypt)
+}
+
+func BenchmarkDecryptCryptoAES(b *testing.B) {
+	c, _ := aes.NewCipher(make([]byte, 16))
+	benchmarkEncrypt(b, c.Decrypt)
+}
@@ -1,3 +1,11 @@
 module github.com/osdeving/aes
 
 go 1.24.2
+
+require github.com/osdeving/modes v0.0.0
+
+replace github.com/osdeving/modes => ../modes
+
+require github.com/osdeving/sboxanalysis v0.0.0
+
+replace github.com/osdeving/sboxanalysis => ../sboxanalysis
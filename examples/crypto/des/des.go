@@ -21,6 +21,7 @@ package main
 
 import (
 	"fmt"
+	"os"
 )
 
 const (
@@ -286,10 +287,12 @@ New: Inicializa uma nova instância da cifra DES com base em uma chave de 64 bit
 
 Parâmetros:
   - key: [8]byte (64 bits) contendo a chave original com bits de paridade.
+  - checks: verificações opcionais da chave (CheckParity, CheckWeak,
+    CheckPossiblyWeak, ver des_keys.go). Sem elas qualquer chave é aceita.
 
 Retorna:
   - *DES: ponteiro para a instância da cifra DES com subchaves geradas.
-  - error: erro caso a geração de subchaves falhe (ex: tamanho incorreto após permutação).
+  - error: *ParityError ou *WeakKeyError quando alguma verificação pedida falha.
 
 Etapas:
  1. Aplica a permutação PC1 na chave original descartando os 8 bits de paridade.
@@ -301,13 +304,21 @@ Obs:
 
 	A função assume que a chave já está no formato exigido pelo padrão DES (8 bytes).
 */
-func New(key [KeySize]byte) (*DES, error) {
+func New(key [KeySize]byte, checks ...KeyCheck) (*DES, error) {
 	d := &DES{}
 
 	err := d.generateSubkeys(key)
 	if err != nil {
 		return nil, err
 	}
+
+	var mask KeyCheck
+	for _, c := range checks {
+		mask |= c
+	}
+	if err := d.checkKey(key, mask); err != nil {
+		return nil, err
+	}
 	return d, nil
 }
 
@@ -399,6 +410,11 @@ Etapas:
 	R (32) -> expansão E (48) -> XOR com subchave -> S-boxes (48 -> 32) -> permutação P (32) -> saída
 */
 func f(R []byte, subkey []byte) []byte {
+	return fTrace(R, subkey, 0, nil)
+}
+
+// fTrace é f enviando a entrada e a saída de cada S-box para o observer.
+func fTrace(R []byte, subkey []byte, round int, obs Observer) []byte {
 	// Etapa 1 Expande R de 32 para 48 bits
 	R48 := permute(R, E[:]) // 6 bytes
 
@@ -418,6 +434,7 @@ func f(R []byte, subkey []byte) []byte {
 		row := ((val & 0x20) >> 4) | (val & 0x01) // bits 1 e 6
 		col := (val >> 1) & 0x0F                  // bits 2 a 5
 		sboxVal := S[i][row*16+col]
+		emit(obs, TraceEvent{Kind: EventSBox, Round: round, SBox: i + 1, In: uint64(val), InBits: 6, Out: uint64(sboxVal), OutBits: 4})
 
 		// Inserir 4 bits resultantes no sboxOut
 		for j := 0; j < 4; j++ {
@@ -594,32 +611,45 @@ Retorno:
   - error: reservado para casos futuros (atualmente sempre nil).
 */
 func (d *DES) Encrypt(plaintext [BlockSize]byte) ([BlockSize]byte, error) {
-	fmt.Printf("Plaintext (hex): %x\n", plaintext)
-	fmt.Printf("Plaintext (bits): %08b\n", plaintext)
+	return d.EncryptTrace(plaintext, nil)
+}
+
+/*
+EncryptTrace: Igual a Encrypt, mas envia cada etapa intermediária (IP,
+subchaves, entradas e saídas de f e das S-boxes, L/R de cada rodada) para o
+observer. Com observer nil não há custo extra além dos testes de nil.
+*/
+func (d *DES) EncryptTrace(plaintext [BlockSize]byte, obs Observer) ([BlockSize]byte, error) {
+	return d.crypt(plaintext, obs, false)
+}
 
-	permuted := permute(plaintext[:], IP[:])
-	fmt.Printf("After IP: %x\n", permuted)
+// crypt executa as 16 rodadas; decrypt só inverte a ordem das subchaves.
+func (d *DES) crypt(input [BlockSize]byte, obs Observer, decrypt bool) ([BlockSize]byte, error) {
+	permuted := permute(input[:], IP[:])
+	emit(obs, TraceEvent{Kind: EventIP, In: toUint64(input[:]), InBits: 64, Out: toUint64(permuted), OutBits: 64})
 
 	L, R := permuted[:4], permuted[4:]
-	fmt.Printf("L0: %x\n", L)
-	fmt.Printf("R0: %x\n", R)
 
 	for i := 0; i < NumRounds; i++ {
-		fOut := f(R, d.subkeys[i][:])
-		fmt.Printf("Round %2d - Subkey: %x\n", i+1, d.subkeys[i])
-		fmt.Printf("Round %2d - f(R, K): %x\n", i+1, fOut)
+		k := i
+		if decrypt {
+			k = NumRounds - 1 - i
+		}
+		round := i + 1
+		emit(obs, TraceEvent{Kind: EventSubkey, Round: round, Out: toUint64(d.subkeys[k][:]), OutBits: 48})
+
+		fOut := fTrace(R, d.subkeys[k][:], round, obs)
+		emit(obs, TraceEvent{Kind: EventF, Round: round, In: toUint64(R), InBits: 32, Out: toUint64(fOut), OutBits: 32})
 
 		Li := R
 		Ri := xor(L, fOut)
-		fmt.Printf("Round %2d - L: %x\n", i+1, Li)
-		fmt.Printf("Round %2d - R: %x\n", i+1, Ri)
 		L, R = Li, Ri
+		emit(obs, TraceEvent{Kind: EventRound, Round: round, In: toUint64(L), InBits: 32, Out: toUint64(R), OutBits: 32})
 	}
 
-	fmt.Printf("Preoutput (R || L): %x%x\n", R, L)
 	preoutput := append(R, L...)
 	finalOutput := permute(preoutput, IPInv[:])
-	fmt.Printf("Final Output: %x\n", finalOutput)
+	emit(obs, TraceEvent{Kind: EventOutput, In: toUint64(preoutput), InBits: 64, Out: toUint64(finalOutput), OutBits: 64})
 
 	var out [BlockSize]byte
 	copy(out[:], finalOutput)
@@ -647,20 +677,12 @@ func (d *DES) Encrypt(plaintext [BlockSize]byte) ([BlockSize]byte, error) {
 */
 
 func (d *DES) Decrypt(ciphertext [BlockSize]byte) ([BlockSize]byte, error) {
-	permuted := permute(ciphertext[:], IP[:])
-	L, R := permuted[:4], permuted[4:]
-
-	for i := NumRounds - 1; i >= 0; i-- {
-		Li := R
-		Ri := xor(L, f(R, d.subkeys[i][:]))
-		L, R = Li, Ri
-	}
+	return d.crypt(ciphertext, nil, true)
+}
 
-	preoutput := append(R, L...)
-	finalOutput := permute(preoutput, IPInv[:])
-	var out [BlockSize]byte
-	copy(out[:], finalOutput)
-	return out, nil
+// DecryptTrace é o Decrypt com observer (ver EncryptTrace).
+func (d *DES) DecryptTrace(ciphertext [BlockSize]byte, obs Observer) ([BlockSize]byte, error) {
+	return d.crypt(ciphertext, obs, true)
 }
 
 /*
@@ -680,9 +702,48 @@ func (d *DES) Decrypt(ciphertext [BlockSize]byte) ([BlockSize]byte, error) {
 
 	Objetivo:
 		- Verificar se a implementação segue o comportamento padronizado do DES.
+
+	Sem argumentos roda o teste FIPS acima; o primeiro argumento escolhe as
+	outras demonstrações (go run . sdes, mitm, diff, linear, brute ou trace).
 */
 
 func main() {
+	cmd := ""
+	if len(os.Args) > 1 {
+		cmd = os.Args[1]
+	}
+
+	switch cmd {
+	case "":
+		runFIPS()
+	case "sdes": // S-DES (sdes_attack.go)
+		runSDES()
+	case "mitm": // meet-in-the-middle contra cifragem dupla (des_mitm.go)
+		runMITM()
+	case "diff": // criptoanálise diferencial do DES de 6 rodadas (des_differential.go)
+		runDifferential()
+	case "linear": // criptoanálise linear de Matsui (des_linear.go)
+		runLinear()
+	case "brute": // busca exaustiva em um subespaço de chaves (des_bruteforce.go)
+		runBruteForce()
+	case "trace": // trace [json|csv]: passo a passo da cifragem (des_trace.go)
+		format := ""
+		if len(os.Args) > 2 {
+			format = os.Args[2]
+		}
+		if err := runTrace(os.Stdout, format); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "comando desconhecido: %q\n", cmd)
+		fmt.Fprintln(os.Stderr, "uso: go run . [sdes | mitm | diff | linear | brute | trace [json|csv]]")
+		os.Exit(2)
+	}
+}
+
+// runFIPS cifra e decifra o bloco do teste FIPS e confere a volta.
+func runFIPS() {
 	// Chave e bloco de teste (padrão FIPS - Federal Information Processing Standards)
 	var chave = [8]byte{0x13, 0x34, 0x57, 0x79, 0x9B, 0xBC, 0xDF, 0xF1}
 	var bloco = [8]byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xAB, 0xCD, 0xEF}
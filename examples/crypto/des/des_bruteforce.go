@@ -0,0 +1,385 @@
+/*
+	Busca exaustiva de chaves do DES
+
+	Propriedade de complementação: complementar a chave e o plaintext
+	complementa o ciphertext,
+
+		E_~K(~P) = ~E_K(P)
+
+	porque ~K dá subchaves complementadas, E(~R) = ~E(R), e os dois
+	complementos se cancelam no XOR antes das S-boxes. Com dois plaintexts
+	escolhidos P e ~P (ciphertexts C1 e C2), cada cifragem testa duas
+	chaves:
+
+		X = E_K(P)
+		X == C1   =>  K é candidata
+		X == ~C2  =>  ~K é candidata (E_~K(~P) = ~X = C2)
+
+	Um subespaço S (bits fixos + bits desconhecidos) é percorrido em |S|
+	cifragens e a busca cobre S e ~S. Sem bits fixos S = ~S e basta
+	percorrer metade: 2^55 cifragens para o DES inteiro.
+
+	Motor:
+
+		- KeySubspace: chave base + posições desconhecidas (TopFixed fixa os
+		  n primeiros bits efetivos, por exemplo 24)
+		- worker pool: um produtor distribui faixas de índices por um canal e
+		  Workers goroutines testam as chaves de cada faixa
+		- key schedule por tabela: as subchaves só selecionam bits da chave,
+		  então são o OR de 8 consultas por rodada (uma por byte), sem
+		  alocação por chave
+		- context.Context cancela a busca; Progress recebe o andamento a
+		  cada ProgressInterval
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrKeyNotFound indica que nenhuma chave do subespaço bate com os pares.
+var ErrKeyNotFound = errors.New("des: chave não encontrada no subespaço")
+
+// keyScheduleTable[r][j][v] são os bits da subchave r gerados pelo byte j da chave valendo v.
+var keyScheduleTable = scheduleTable()
+
+func scheduleTable() *[NumRounds][KeySize][256]uint64 {
+	var bitTable [64][NumRounds]uint64
+	for p := 0; p < 64; p++ {
+		var key [KeySize]byte
+		setBit(key[:], p, 1)
+		d, _ := New(key)
+		for r, k := range d.subkeys {
+			bitTable[p][r] = toUint64(k[:])
+		}
+	}
+
+	t := new([NumRounds][KeySize][256]uint64)
+	for r := 0; r < NumRounds; r++ {
+		for j := 0; j < KeySize; j++ {
+			for v := 0; v < 256; v++ {
+				for b := 0; b < 8; b++ {
+					if v>>(7-b)&1 == 1 {
+						t[r][j][v] |= bitTable[8*j+b][r]
+					}
+				}
+			}
+		}
+	}
+	return t
+}
+
+// fastKeySchedule gera as 16 subchaves de uma chave de 64 bits (MSB = bit 1).
+func fastKeySchedule(key uint64, d *FastDES) {
+	for r := 0; r < NumRounds; r++ {
+		t := &keyScheduleTable[r]
+		d.subkeys[r] = t[0][key>>56] | t[1][key>>48&0xff] | t[2][key>>40&0xff] | t[3][key>>32&0xff] |
+			t[4][key>>24&0xff] | t[5][key>>16&0xff] | t[6][key>>8&0xff] | t[7][key&0xff]
+	}
+	d.rounds = NumRounds
+}
+
+// KeySubspace é o conjunto de chaves iguais a Base fora das posições Unknown (0-based, MSB primeiro).
+type KeySubspace struct {
+	Base    [KeySize]byte
+	Unknown []int
+}
+
+/*
+TopFixed: Subespaço com os `fixed` primeiros bits efetivos de base fixos
+e os 56-fixed restantes desconhecidos. Bits de paridade não contam.
+*/
+func TopFixed(base [KeySize]byte, fixed int) KeySubspace {
+	s := KeySubspace{Base: base}
+	n := 0
+	for pos := 0; pos < 64; pos++ {
+		if pos%8 == 7 {
+			continue
+		}
+		if n >= fixed {
+			s.Unknown = append(s.Unknown, pos)
+		}
+		n++
+	}
+	return s
+}
+
+/*
+validate: Confere as posições desconhecidas. Fora de 0..63 o deslocamento
+em key estouraria dentro de um worker; bits de paridade e posições
+repetidas não mudam a chave e só dobrariam o trabalho.
+*/
+func (s KeySubspace) validate() error {
+	seen := make(map[int]bool, len(s.Unknown))
+	for _, pos := range s.Unknown {
+		switch {
+		case pos < 0 || pos > 63:
+			return fmt.Errorf("des: posição desconhecida %d fora de 0 a 63", pos)
+		case pos%8 == 7:
+			return fmt.Errorf("des: posição desconhecida %d é um bit de paridade", pos)
+		case seen[pos]:
+			return fmt.Errorf("des: posição desconhecida %d repetida", pos)
+		}
+		seen[pos] = true
+	}
+	return nil
+}
+
+// Size é o número de chaves do subespaço.
+func (s KeySubspace) Size() uint64 { return 1 << len(s.Unknown) }
+
+// key devolve a i-ésima chave do subespaço como uint64.
+func (s KeySubspace) key(base uint64, i uint64) uint64 {
+	for j, pos := range s.Unknown {
+		bit := uint64(1) << (63 - pos)
+		if i>>j&1 == 1 {
+			base |= bit
+		} else {
+			base &^= bit
+		}
+	}
+	return base
+}
+
+// BruteForceProgress é o andamento da busca.
+type BruteForceProgress struct {
+	Tested  uint64 // cifragens feitas
+	Total   uint64 // cifragens do subespaço inteiro
+	Elapsed time.Duration
+}
+
+// String mostra o andamento; a taxa só aparece depois que algum tempo passou.
+func (p BruteForceProgress) String() string {
+	out := fmt.Sprintf("%d/%d (%.1f%%)", p.Tested, p.Total, 100*float64(p.Tested)/float64(p.Total))
+	if p.Elapsed > 0 {
+		out += fmt.Sprintf(", %.0f cifragens/s", float64(p.Tested)/p.Elapsed.Seconds())
+	}
+	return out
+}
+
+// BruteForceOptions configura o motor de busca.
+type BruteForceOptions struct {
+	Workers          int  // goroutines; <= 0 usa runtime.NumCPU()
+	Complement       bool // usa a propriedade de complementação (exige os pares P e ~P)
+	Progress         func(BruteForceProgress)
+	ProgressInterval time.Duration // padrão: 1s
+}
+
+// BruteForceResult traz a chave encontrada e o custo da busca.
+type BruteForceResult struct {
+	Key      [KeySize]byte // chave com paridade ajustada
+	Found    bool
+	Tested   uint64 // cifragens feitas
+	Covered  uint64 // chaves testadas (2 por cifragem com Complement)
+	Elapsed  time.Duration
+	Workers  int
+	Complete bool // o subespaço foi percorrido inteiro (sem cancelamento)
+}
+
+// bruteForceChunk é quantas chaves cada worker testa por tarefa.
+const bruteForceChunk = 1 << 12
+
+/*
+BruteForce: Procura no subespaço a chave que cifra todos os pares.
+
+	pairs[0] é testado em toda chave; os demais confirmam os candidatos.
+	Com opts.Complement, pairs precisa ter também o par com plaintext
+	~pairs[0].Plaintext, e a busca cobre s e ~s.
+
+Para na primeira chave que confirma todos os pares. Se ctx for cancelado,
+devolve o resultado parcial e ctx.Err(); se o subespaço acabar sem chave,
+devolve ErrKeyNotFound. Posições desconhecidas fora de 0..63, repetidas ou
+de paridade são recusadas antes de a busca começar.
+*/
+func BruteForce(ctx context.Context, pairs []KnownPair, s KeySubspace, opts BruteForceOptions) (*BruteForceResult, error) {
+	if len(pairs) == 0 {
+		return nil, fmt.Errorf("des: é preciso pelo menos um par conhecido")
+	}
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	interval := opts.ProgressInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	p0, c0 := pairs[0].Plaintext, pairs[0].Ciphertext
+	var notC2 uint64 // ~C2 da definição acima
+	total := s.Size()
+	if opts.Complement {
+		found := false
+		for _, p := range pairs {
+			if p.Plaintext == ^p0 {
+				notC2, found = ^p.Ciphertext, true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("des: a complementação exige o par com plaintext %016x", ^p0)
+		}
+		// Sem bits fixos S = ~S: basta a metade com o último bit desconhecido em 0
+		if len(s.Unknown) == 56 {
+			total /= 2
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	base := toUint64(s.Base[:])
+	res := &BruteForceResult{Workers: workers}
+	var tested atomic.Uint64
+	var once sync.Once
+	start := time.Now()
+
+	report := func() {
+		if opts.Progress != nil {
+			opts.Progress(BruteForceProgress{tested.Load(), total, time.Since(start)})
+		}
+	}
+
+	// produtor: faixas [lo, hi) do índice das chaves
+	jobs := make(chan uint64)
+	go func() {
+		defer close(jobs)
+		for lo := uint64(0); lo < total; lo += bruteForceChunk {
+			select {
+			case jobs <- lo:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var d FastDES
+			for lo := range jobs {
+				hi := min(lo+bruteForceChunk, total)
+				for i := lo; i < hi; i++ {
+					k := s.key(base, i)
+					fastKeySchedule(k, &d)
+					x := d.EncryptUint64(p0)
+
+					var cand uint64
+					switch {
+					case x == c0:
+						cand = k
+					case opts.Complement && x == notC2:
+						cand = ^k
+					default:
+						continue
+					}
+					if confirmKey(cand, pairs) {
+						once.Do(func() {
+							var key [KeySize]byte
+							for j := range key {
+								key[j] = byte(cand >> (56 - 8*j))
+							}
+							res.Key, res.Found = FixParity(key), true
+							cancel()
+						})
+					}
+				}
+				tested.Add(hi - lo)
+				if ctx.Err() != nil {
+					return
+				}
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+loop:
+	for {
+		select {
+		case <-ticker.C:
+			report()
+		case <-done:
+			break loop
+		}
+	}
+	report()
+
+	res.Tested = tested.Load()
+	res.Covered = res.Tested
+	if opts.Complement {
+		res.Covered *= 2
+	}
+	res.Elapsed = time.Since(start)
+	res.Complete = res.Tested == total
+
+	switch {
+	case res.Found:
+		return res, nil
+	case !res.Complete:
+		return res, context.Cause(ctx)
+	default:
+		return res, ErrKeyNotFound
+	}
+}
+
+// confirmKey confere uma chave candidata com todos os pares.
+func confirmKey(key uint64, pairs []KnownPair) bool {
+	var d FastDES
+	fastKeySchedule(key, &d)
+	for _, p := range pairs {
+		if d.EncryptUint64(p.Plaintext) != p.Ciphertext {
+			return false
+		}
+	}
+	return true
+}
+
+// runBruteForce é a demonstração: go run . brute
+func runBruteForce() {
+	key := [KeySize]byte{0x13, 0x34, 0x57, 0x79, 0x9B, 0xBC, 0xDF, 0xF1}
+	d, _ := NewFastDES(key)
+	p := uint64(0x0123456789ABCDEF)
+	pairs := []KnownPair{{p, d.EncryptUint64(p)}, {^p, d.EncryptUint64(^p)}}
+
+	// O atacante conhece os 36 primeiros bits efetivos da chave complementada
+	var guess [KeySize]byte
+	for i := range key {
+		guess[i] = ^key[i]
+	}
+	s := TopFixed(guess, 36)
+	fmt.Printf("Subespaço: %d bits desconhecidos (%d chaves), base %x\n", len(s.Unknown), s.Size(), guess)
+
+	for _, complement := range []bool{false, true} {
+		res, err := BruteForce(context.Background(), pairs, s, BruteForceOptions{
+			Complement: complement,
+			Progress: func(p BruteForceProgress) {
+				fmt.Fprintf(os.Stderr, "  %v\n", p)
+			},
+		})
+		fmt.Printf("Complementação %v: ", complement)
+		if err != nil {
+			fmt.Printf("%v após %d cifragens em %v\n", err, res.Tested, res.Elapsed)
+			continue
+		}
+		fmt.Printf("chave %x após %d cifragens (%d chaves) em %v com %d worker(s)\n", res.Key, res.Tested, res.Covered, res.Elapsed, res.Workers)
+	}
+}
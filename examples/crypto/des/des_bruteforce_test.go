@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestComplementationProperty(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		var key, notKey [KeySize]byte
+		rng.Read(key[:])
+		for j := range key {
+			notKey[j] = ^key[j]
+		}
+		d, _ := NewFastDES(key)
+		nd, _ := NewFastDES(notKey)
+
+		p := rng.Uint64()
+		if got, want := nd.EncryptUint64(^p), ^d.EncryptUint64(p); got != want {
+			t.Fatalf("chave %x: E_~K(~P) = %016x, ~E_K(P) = %016x", key, got, want)
+		}
+	}
+}
+
+func TestFastKeySchedule(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	for i := 0; i < 20; i++ {
+		var key [KeySize]byte
+		rng.Read(key[:])
+		ref, _ := NewFastDES(key)
+
+		var d FastDES
+		fastKeySchedule(toUint64(key[:]), &d)
+		if d != *ref {
+			t.Fatalf("chave %x: subchaves diferentes", key)
+		}
+	}
+}
+
+func TestTopFixed(t *testing.T) {
+	s := TopFixed(fipsKey, 24)
+	if len(s.Unknown) != 32 || s.Size() != 1<<32 {
+		t.Fatalf("%d bits desconhecidos", len(s.Unknown))
+	}
+	for _, pos := range s.Unknown {
+		if pos%8 == 7 {
+			t.Errorf("bit de paridade %d no subespaço", pos)
+		}
+	}
+	// 24 bits efetivos = 3 bytes e meio (7 bits por byte)
+	if s.Unknown[0] != 27 {
+		t.Errorf("primeiro bit desconhecido %d, esperado 27", s.Unknown[0])
+	}
+}
+
+// bruteForcePairs devolve uma chave aleatória e os pares (P, C) e (~P, C').
+func bruteForcePairs(seed int64) ([KeySize]byte, []KnownPair) {
+	rng := rand.New(rand.NewSource(seed))
+	var key [KeySize]byte
+	rng.Read(key[:])
+	d, _ := NewFastDES(key)
+	p := rng.Uint64()
+	return FixParity(key), []KnownPair{{p, d.EncryptUint64(p)}, {^p, d.EncryptUint64(^p)}}
+}
+
+func TestBruteForce20Bits(t *testing.T) {
+	key, pairs := bruteForcePairs(3)
+
+	var last BruteForceProgress
+	res, err := BruteForce(context.Background(), pairs, TopFixed(key, 36), BruteForceOptions{
+		Workers:          4,
+		Progress:         func(p BruteForceProgress) { last = p },
+		ProgressInterval: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Key != key {
+		t.Errorf("chave %x, esperado %x", res.Key, key)
+	}
+	if res.Tested > 1<<20 || res.Workers != 4 {
+		t.Errorf("%d cifragens com %d workers", res.Tested, res.Workers)
+	}
+	// O último relatório é feito depois dos workers terminarem
+	if last.Tested != res.Tested || last.Total != 1<<20 {
+		t.Errorf("último progresso %d/%d, resultado %d", last.Tested, last.Total, res.Tested)
+	}
+}
+
+func TestBruteForceSubespacoInvalido(t *testing.T) {
+	_, pairs := bruteForcePairs(6)
+	for _, unknown := range [][]int{{64}, {-1}, {7}, {0, 1, 0}} {
+		s := KeySubspace{Base: fipsKey, Unknown: unknown}
+		if _, err := BruteForce(context.Background(), pairs, s, BruteForceOptions{}); err == nil {
+			t.Errorf("posições %v aceitas", unknown)
+		}
+	}
+}
+
+// Com a base complementada a chave está em ~S: só a complementação a encontra.
+func TestBruteForceComplement(t *testing.T) {
+	key, pairs := bruteForcePairs(4)
+	var notKey [KeySize]byte
+	for i := range key {
+		notKey[i] = ^key[i]
+	}
+	s := TopFixed(notKey, 40)
+
+	if _, err := BruteForce(context.Background(), pairs, s, BruteForceOptions{}); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("sem complementação: err = %v", err)
+	}
+
+	res, err := BruteForce(context.Background(), pairs, s, BruteForceOptions{Complement: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Key != key || res.Covered != 2*res.Tested {
+		t.Errorf("chave %x (esperado %x), %d cifragens, %d chaves", res.Key, key, res.Tested, res.Covered)
+	}
+
+	if _, err := BruteForce(context.Background(), pairs[:1], s, BruteForceOptions{Complement: true}); err == nil {
+		t.Error("esperado erro sem o par complementar")
+	}
+}
+
+func TestBruteForceCancel(t *testing.T) {
+	key, pairs := bruteForcePairs(5)
+	key[0] ^= 0x80 // fora do subespaço: a busca não termina sozinha tão cedo
+
+	// O próprio callback cancela no primeiro relatório, sem depender do relógio
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var reports []BruteForceProgress
+	res, err := BruteForce(ctx, pairs, TopFixed(key, 16), BruteForceOptions{
+		Workers: 2,
+		Progress: func(p BruteForceProgress) {
+			reports = append(reports, p)
+			cancel()
+		},
+		ProgressInterval: time.Millisecond,
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v", err)
+	}
+	if res.Found || res.Complete || res.Tested >= 1<<40 {
+		t.Errorf("resultado parcial inesperado: %+v", res)
+	}
+	if reports[0].Total != 1<<40 || reports[0].Tested >= reports[0].Total {
+		t.Errorf("primeiro relatório: %v", reports[0])
+	}
+}
+
+func TestBruteForceProgressString(t *testing.T) {
+	p := BruteForceProgress{Tested: 0, Total: 1 << 20}
+	if got := p.String(); got != "0/1048576 (0.0%)" {
+		t.Errorf("sem tempo decorrido: %q", got)
+	}
+	p = BruteForceProgress{Tested: 1 << 19, Total: 1 << 20, Elapsed: time.Second}
+	if got := p.String(); got != "524288/1048576 (50.0%), 524288 cifragens/s" {
+		t.Errorf("com 1s: %q", got)
+	}
+}
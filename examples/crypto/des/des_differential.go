@@ -0,0 +1,518 @@
+/*
+	Criptoanálise diferencial do DES reduzido (Biham e Shamir, 1990)
+
+	Pares de plaintexts com diferença fixa (P XOR P* = Ω_P) atravessam as
+	rodadas de forma previsível. Só as S-boxes são não lineares; E, P e o
+	XOR com a subchave propagam a diferença sem depender da chave:
+
+		ΔE(R) = E(ΔR)       Δ(x XOR K) = Δx       ΔP(y) = P(Δy)
+
+	Para cada S-box, a DDT (tabela de distribuição de diferenças) diz com
+	que probabilidade uma diferença de entrada vira uma de saída:
+
+		DDT_i[Δin][Δout] = #{x : S_i(x) XOR S_i(x XOR Δin) = Δout}   (de 64)
+
+	Característica: sequência de diferenças rodada a rodada. A probabilidade
+	de uma rodada é o produto das DDTs das S-boxes ativas (entrada não nula);
+	a da característica é o produto das rodadas. Com ΔR = 0 a rodada passa
+	com probabilidade 1, o que permite características de 3 rodadas com
+	probabilidade 1/16:
+
+		ΔL0 ΔR0 = 40080000 04000000
+		rodada 1: f(04000000) -> 40080000   p = 1/4 (S2: 08 -> A)
+		rodada 2: f(00000000) -> 00000000   p = 1
+		rodada 3: f(04000000) -> 40080000   p = 1/4
+		ΔL3 ΔR3 = 04000000 40080000
+
+	Ataque a 6 rodadas (texto claro escolhido). Nas três últimas rodadas:
+
+		R6 = L5 XOR f(R5, K6) = L3 XOR f(R3, K4) XOR f(R5, K6)
+
+	O atacante conhece R5 = L6 (do ciphertext) e ΔL3 (da característica).
+	As S-boxes de f(R3, K4) com entrada inativa em ΔR3 têm saída nula; para
+	elas a diferença de saída na rodada 6 é conhecida:
+
+		Δsaída = P^-1(ΔR6 XOR ΔL3)
+
+	Cada par certo sugere os 6 bits de K6 de cada uma dessas S-boxes; a
+	subchave correta recebe ~1/16 dos votos a mais que as outras. Pares cuja
+	diferença é impossível na DDT de alguma S-box são descartados (filtro).
+
+	Com as duas características de Biham-Shamir saem 7 S-boxes (42 bits de
+	K6); os 14 bits restantes da chave são testados por força bruta.
+
+	A busca de características (SearchCharacteristic) percorre em
+	profundidade as saídas possíveis de cada rodada na DDT, limitando o
+	número de S-boxes ativas e podando ramos piores que o melhor já achado.
+*/
+
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"math/rand"
+	"strings"
+)
+
+// sboxDDT[i][Δin][Δout] é a DDT da S-box i do DES.
+var sboxDDT = desDDT()
+
+// sboxOut[i][v] é a saída v da S-box i já permutada por P.
+var sboxOut = sboxOutTable()
+
+// desSbox consulta a S-box i com 6 bits b1..b6: linha = b1b6, coluna = b2..b5.
+func desSbox(i int, x uint32) uint32 {
+	row := (x&0x20)>>4 | x&0x01
+	col := (x >> 1) & 0x0F
+	return uint32(S[i][row*16+col])
+}
+
+func desDDT() [8][64][16]int {
+	var ddt [8][64][16]int
+	for i := 0; i < 8; i++ {
+		for in := uint32(0); in < 64; in++ {
+			for x := uint32(0); x < 64; x++ {
+				ddt[i][in][desSbox(i, x)^desSbox(i, x^in)]++
+			}
+		}
+	}
+	return ddt
+}
+
+func sboxOutTable() [8][16]uint32 {
+	var t [8][16]uint32
+	for i := 0; i < 8; i++ {
+		for v := 0; v < 16; v++ {
+			t[i][v] = permute32(uint32(v)<<(28-4*i), P[:])
+		}
+	}
+	return t
+}
+
+// sboxInput devolve os 6 bits de E(r) que entram na S-box i (mesma rotação de fastF).
+func sboxInput(r uint32, i int) uint32 {
+	return bits.RotateLeft32(r, 4*i-1) >> 26
+}
+
+// sboxOutput devolve a saída da S-box i contida em y, a saída de f: o nibble i de P^-1(y).
+func sboxOutput(y uint32, i int) uint32 {
+	var v uint32
+	for j, pos := range P {
+		if int(pos-1)/4 == i {
+			v |= (y >> (31 - uint(j)) & 1) << (3 - uint(pos-1)%4)
+		}
+	}
+	return v
+}
+
+// activeSboxes conta as S-boxes com diferença de entrada não nula.
+func activeSboxes(a uint32) int {
+	n := 0
+	for i := 0; i < 8; i++ {
+		if sboxInput(a, i) != 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// roundProbability é a probabilidade de f levar a diferença a em b.
+func roundProbability(a, b uint32) float64 {
+	p := 1.0
+	for i := 0; i < 8; i++ {
+		p *= float64(sboxDDT[i][sboxInput(a, i)][sboxOutput(b, i)]) / 64
+	}
+	return p
+}
+
+// CharacteristicRound é uma rodada: f leva a diferença In em Out com probabilidade Prob.
+type CharacteristicRound struct {
+	In, Out uint32
+	Prob    float64
+}
+
+// Characteristic é uma característica diferencial a partir de (ΔL0, ΔR0).
+type Characteristic struct {
+	L, R   uint32
+	Rounds []CharacteristicRound
+	Prob   float64
+}
+
+/*
+NewCharacteristic: Monta a característica que parte de (l, r) e em que a
+saída de f na rodada i é fOut[i]. As probabilidades saem da DDT.
+*/
+func NewCharacteristic(l, r uint32, fOut ...uint32) Characteristic {
+	c := Characteristic{L: l, R: r, Prob: 1}
+	for _, b := range fOut {
+		p := roundProbability(r, b)
+		c.Rounds = append(c.Rounds, CharacteristicRound{r, b, p})
+		c.Prob *= p
+		l, r = r, l^b
+	}
+	return c
+}
+
+// Output devolve a diferença prevista (ΔLn, ΔRn) depois das rodadas.
+func (c Characteristic) Output() (uint32, uint32) {
+	l, r := c.L, c.R
+	for _, rd := range c.Rounds {
+		l, r = r, l^rd.Out
+	}
+	return l, r
+}
+
+func (c Characteristic) String() string {
+	if c.Prob == 0 {
+		return "nenhuma característica"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "ΔL0 ΔR0 = %08x %08x\n", c.L, c.R)
+	for i, rd := range c.Rounds {
+		fmt.Fprintf(&b, "rodada %d: f(%08x) -> %08x   p = %.6g\n", i+1, rd.In, rd.Out, rd.Prob)
+	}
+	l, r := c.Output()
+	fmt.Fprintf(&b, "ΔL%d ΔR%d = %08x %08x   p = %.6g (2^%.2f)", len(c.Rounds), len(c.Rounds), l, r, c.Prob, math.Log2(c.Prob))
+	return b.String()
+}
+
+// BihamShamir3Round são as duas características de 3 rodadas (p = 1/16)
+// usadas no ataque a 6 rodadas. Juntas deixam só S3 sem contagem.
+var BihamShamir3Round = []Characteristic{
+	NewCharacteristic(0x40080000, 0x04000000, 0x40080000, 0, 0x40080000),
+	NewCharacteristic(0x00200008, 0x00000400, 0x00200008, 0, 0x00200008),
+}
+
+// fOutputs chama fn para cada saída b de f possível com entrada a, com sua probabilidade.
+func fOutputs(a uint32, fn func(b uint32, p float64)) {
+	var walk func(i int, b uint32, p float64)
+	walk = func(i int, b uint32, p float64) {
+		if i == 8 {
+			fn(b, p)
+			return
+		}
+		in := sboxInput(a, i)
+		for out, n := range sboxDDT[i][in] {
+			if n > 0 {
+				walk(i+1, b|sboxOut[i][out], p*float64(n)/64)
+			}
+		}
+	}
+	walk(0, 0, 1)
+}
+
+// lowDiffs devolve as diferenças de 32 bits com no máximo maxActive S-boxes ativas (inclui zero).
+func lowDiffs(maxActive int) []uint32 {
+	// masks[i] são os bits de R que entram na S-box i
+	var masks [8]uint32
+	for i := 0; i < 8; i++ {
+		masks[i] = bits.RotateLeft32(0xFC000000, 1-4*i)
+	}
+
+	seen := map[uint32]bool{0: true}
+	diffs := []uint32{0}
+	for set := 1; set < 256; set++ {
+		if bits.OnesCount(uint(set)) > maxActive {
+			continue
+		}
+		var allowed uint32
+		for i := 0; i < 8; i++ {
+			if set&(1<<i) != 0 {
+				allowed |= masks[i]
+			}
+		}
+		for i := 0; i < 8; i++ {
+			if set&(1<<i) == 0 {
+				allowed &^= masks[i]
+			}
+		}
+		// percorre os subconjuntos de allowed
+		for a := allowed; a != 0; a = (a - 1) & allowed {
+			if !seen[a] {
+				seen[a] = true
+				diffs = append(diffs, a)
+			}
+		}
+	}
+	return diffs
+}
+
+type characteristicSearch struct {
+	rounds    int
+	maxActive int
+	bound     []float64 // bound[k]: melhor probabilidade com k rodadas (bound[0] = 1)
+	l0        uint32
+	path      []CharacteristicRound
+	best      Characteristic
+}
+
+// promising diz se um prefixo com probabilidade p ainda pode superar a melhor.
+func (s *characteristicSearch) promising(p float64) bool {
+	return p*s.bound[s.rounds-len(s.path)-1] > s.best.Prob
+}
+
+func (s *characteristicSearch) extend(l, r uint32, p float64) {
+	if len(s.path) == s.rounds {
+		if p > s.best.Prob {
+			s.best = Characteristic{L: s.l0, R: s.path[0].In, Prob: p}
+			s.best.Rounds = append([]CharacteristicRound(nil), s.path...)
+		}
+		return
+	}
+
+	last := len(s.path) == s.rounds-1
+	fOutputs(r, func(b uint32, q float64) {
+		if !s.promising(p * q) {
+			return
+		}
+		next := l ^ b
+		if !last && activeSboxes(next) > s.maxActive {
+			return
+		}
+		s.path = append(s.path, CharacteristicRound{r, b, q})
+		s.extend(r, next, p*q)
+		s.path = s.path[:len(s.path)-1]
+	})
+}
+
+/*
+SearchCharacteristic: Procura a característica de `rounds` rodadas com
+maior probabilidade em que nenhuma rodada (exceto a saída) tem mais que
+maxActive S-boxes ativas.
+
+	ΔR0 e ΔR1 são escolhidos entre as diferenças com poucas S-boxes ativas;
+	ΔL0 = ΔR1 XOR f-saída da rodada 1. Depois a busca segue em
+	profundidade pelas saídas possíveis de f (DDT).
+
+	Poda como no algoritmo de Matsui: as melhores probabilidades B_k com
+	k < rounds rodadas são calculadas antes, e um prefixo de i rodadas com
+	probabilidade p só continua se p * B_(rounds-i) superar a melhor
+	característica já encontrada.
+
+Devolve Prob = 0 se nenhuma característica respeita maxActive. Com
+maxActive = 1 a busca é instantânea (mas não há característica de 4 ou
+mais rodadas); com 2 ela encontra a de 4 rodadas com p = 2^-9.61 em
+dezenas de segundos.
+*/
+func SearchCharacteristic(rounds, maxActive int) Characteristic {
+	bound := []float64{1}
+	for k := 1; k < rounds; k++ {
+		bound = append(bound, searchCharacteristic(k, maxActive, bound).Prob)
+	}
+	return searchCharacteristic(rounds, maxActive, bound)
+}
+
+func searchCharacteristic(rounds, maxActive int, bound []float64) Characteristic {
+	s := &characteristicSearch{rounds: rounds, maxActive: maxActive, bound: bound}
+	diffs := lowDiffs(maxActive)
+
+	for _, r0 := range diffs {
+		fOutputs(r0, func(b uint32, q float64) {
+			s.path = s.path[:0]
+			if !s.promising(q) {
+				return
+			}
+			for _, r1 := range diffs {
+				l0 := r1 ^ b
+				if l0 == 0 && r0 == 0 {
+					continue
+				}
+				s.l0 = l0
+				s.path = append(s.path[:0], CharacteristicRound{r0, b, q})
+				s.extend(r0, r1, q)
+			}
+		})
+	}
+	return s.best
+}
+
+// DifferentialResult traz a subchave da última rodada e a chave recuperadas.
+type DifferentialResult struct {
+	Subkey   uint64        // subchave da última rodada (48 bits)
+	Voted    uint64        // bits de Subkey vindos da contagem, antes da busca final
+	Key      [KeySize]byte // chave com paridade ajustada
+	Counted  [8]bool       // S-boxes cujos 6 bits saíram da contagem
+	Pairs    int           // pares escolhidos (dois plaintexts cada)
+	Filtered int           // pares que passaram pelo filtro
+	Trials   int           // chaves testadas na busca final
+}
+
+/*
+DifferentialAttack: Recupera a chave de um DES reduzido a n+3 rodadas a
+partir de um oráculo de cifragem e de características de n rodadas (o caso
+clássico é n = 3 com BihamShamir3Round, ou seja, DES de 6 rodadas).
+
+	encrypt cifra um bloco com a chave desconhecida, por exemplo o método
+	EncryptUint64 de NewFastDESWithRounds(chave, 6).
+
+Para cada característica são cifrados `pairs` pares com diferença
+(ΔL0, ΔR0) depois de IP; a seed fixa os plaintexts escolhidos.
+*/
+func DifferentialAttack(encrypt func(uint64) uint64, chars []Characteristic, pairs int, seed int64) (*DifferentialResult, error) {
+	if len(chars) == 0 {
+		return nil, fmt.Errorf("diferencial: nenhuma característica")
+	}
+	rounds := len(chars[0].Rounds) + 3
+	rng := rand.New(rand.NewSource(seed))
+	res := &DifferentialResult{}
+
+	var counts [8][64]int
+	for _, ch := range chars {
+		if len(ch.Rounds)+3 != rounds {
+			return nil, fmt.Errorf("diferencial: características com números de rodadas diferentes")
+		}
+		l3, r3 := ch.Output()
+		var boxes []int
+		for i := 0; i < 8; i++ {
+			if sboxInput(r3, i) == 0 {
+				boxes = append(boxes, i)
+				res.Counted[i] = true
+			}
+		}
+
+		delta := uint64(ch.L)<<32 | uint64(ch.R)
+		for n := 0; n < pairs; n++ {
+			// x e x^delta são os blocos depois de IP; IPInv os leva ao plaintext
+			x := rng.Uint64()
+			y := permute64(encrypt(permute64(x, &fpTable)), &ipTable)
+			ys := permute64(encrypt(permute64(x^delta, &fpTable)), &ipTable)
+			res.Pairs++
+
+			// y = R || L da última rodada; L é a entrada de f na última rodada
+			l, ls := uint32(y), uint32(ys)
+			dOut := uint32(y>>32) ^ uint32(ys>>32) ^ l3
+
+			var cands [8][]int
+			ok := true
+			for _, i := range boxes {
+				cands[i] = sboxKeyCandidates(i, sboxInput(l, i), sboxInput(ls, i), sboxOutput(dOut, i))
+				if len(cands[i]) == 0 {
+					ok = false
+					break
+				}
+			}
+			if !ok {
+				continue
+			}
+			res.Filtered++
+			for _, i := range boxes {
+				for _, k := range cands[i] {
+					counts[i][k]++
+				}
+			}
+		}
+	}
+
+	for i := 0; i < 8; i++ {
+		if !res.Counted[i] {
+			continue
+		}
+		best := 0
+		for k := range counts[i] {
+			if counts[i][k] > counts[i][best] {
+				best = k
+			}
+		}
+		res.Voted |= uint64(best) << (42 - 6*i)
+	}
+	res.Subkey = res.Voted
+
+	if err := res.searchKey(encrypt, rounds); err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+// sboxKeyCandidates devolve os k com S_i(e XOR k) XOR S_i(es XOR k) = out.
+func sboxKeyCandidates(i int, e, es, out uint32) []int {
+	var ks []int
+	for k := uint32(0); k < 64; k++ {
+		if desSbox(i, e^k)^desSbox(i, es^k) == out {
+			ks = append(ks, int(k))
+		}
+	}
+	return ks
+}
+
+/*
+subkeyPositions: Para cada bit j da subchave da rodada `round` (0 = MSB dos
+48), a posição 0-based do bit da chave que o gera. O key schedule só
+desloca e seleciona bits, então basta ligar um bit de cada vez.
+*/
+func subkeyPositions(round int) [48]int {
+	var pos [48]int
+	for p := 0; p < 64; p++ {
+		if p%8 == 7 {
+			continue
+		}
+		var key [KeySize]byte
+		setBit(key[:], p, 1)
+		d, _ := New(key)
+		k := toUint64(d.subkeys[round-1][:])
+		if k != 0 {
+			pos[47-bits.TrailingZeros64(k)] = p
+		}
+	}
+	return pos
+}
+
+// searchKey completa a chave: fixa os bits vindos das S-boxes contadas e testa os restantes.
+func (res *DifferentialResult) searchKey(encrypt func(uint64) uint64, rounds int) error {
+	var base [KeySize]byte
+	known := make(map[int]bool)
+	for j, p := range subkeyPositions(rounds) {
+		if !res.Counted[j/6] {
+			continue
+		}
+		setBit(base[:], p, byte(res.Subkey>>(47-j))&1)
+		known[p] = true
+	}
+
+	var unknown []int
+	for p := 0; p < 64; p++ {
+		if p%8 != 7 && !known[p] {
+			unknown = append(unknown, p)
+		}
+	}
+
+	tests := []uint64{0x0123456789ABCDEF, 0xFEDCBA9876543210}
+	want := []uint64{encrypt(tests[0]), encrypt(tests[1])}
+
+	for k := uint64(0); k < 1<<len(unknown); k++ {
+		key := ReducedDESKey(base, unknown, k)
+		d, err := NewFastDESWithRounds(key, rounds)
+		if err != nil {
+			return err
+		}
+		res.Trials++
+		if d.EncryptUint64(tests[0]) == want[0] && d.EncryptUint64(tests[1]) == want[1] {
+			res.Key = FixParity(key)
+			res.Subkey = d.subkeys[rounds-1]
+			return nil
+		}
+	}
+	return fmt.Errorf("diferencial: nenhuma chave compatível; use mais pares")
+}
+
+// runDifferential é a demonstração: go run . diff
+func runDifferential() {
+	fmt.Println("DDT de S1, linha Δentrada = 34:", sboxDDT[0][0x34])
+	fmt.Println()
+
+	for rounds := 1; rounds <= 3; rounds++ {
+		fmt.Printf("Melhor característica de %d rodada(s) com 1 S-box ativa por rodada:\n%v\n\n", rounds, SearchCharacteristic(rounds, 1))
+	}
+
+	key := [KeySize]byte{0x13, 0x34, 0x57, 0x79, 0x9B, 0xBC, 0xDF, 0xF1}
+	d, _ := NewFastDESWithRounds(key, 6)
+	res, err := DifferentialAttack(d.EncryptUint64, BihamShamir3Round, 200, 1)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Printf("DES de 6 rodadas: %d pares, %d passaram pelo filtro, %d chaves testadas\n", res.Pairs, res.Filtered, res.Trials)
+	fmt.Printf("  K6 recuperada: %012x (real: %012x)\n", res.Subkey, d.subkeys[5])
+	fmt.Printf("  chave:         %x\n", res.Key)
+}
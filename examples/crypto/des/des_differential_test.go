@@ -0,0 +1,130 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestDESDDT(t *testing.T) {
+	for i := 0; i < 8; i++ {
+		if sboxDDT[i][0][0] != 64 {
+			t.Errorf("S%d: DDT[0][0] = %d", i+1, sboxDDT[i][0][0])
+		}
+		for in := 1; in < 64; in++ {
+			sum := 0
+			for out := 0; out < 16; out++ {
+				sum += sboxDDT[i][in][out]
+				if sboxDDT[i][in][out] > 16 {
+					t.Errorf("S%d: DDT[%02x][%x] = %d > 16", i+1, in, out, sboxDDT[i][in][out])
+				}
+			}
+			if sum != 64 {
+				t.Errorf("S%d: linha %02x soma %d", i+1, in, sum)
+			}
+		}
+		// Critério de projeto: S(x) != S(x XOR 001100)
+		if sboxDDT[i][0x0C][0] != 0 {
+			t.Errorf("S%d: DDT[0c][0] = %d", i+1, sboxDDT[i][0x0C][0])
+		}
+	}
+	// Exemplo de Biham-Shamir: S1, Δentrada 34 -> Δsaída 1..F
+	want := [16]int{0, 8, 16, 6, 2, 0, 0, 12, 6, 0, 0, 0, 0, 8, 0, 6}
+	if sboxDDT[0][0x34] != want {
+		t.Errorf("DDT de S1 linha 34 = %v", sboxDDT[0][0x34])
+	}
+}
+
+func TestSboxOutput(t *testing.T) {
+	for i := 0; i < 8; i++ {
+		for v := uint32(0); v < 16; v++ {
+			if got := sboxOutput(sboxOut[i][v], i); got != v {
+				t.Errorf("S%d: sboxOutput(P(%x)) = %x", i+1, v, got)
+			}
+		}
+	}
+}
+
+func TestBihamShamirCharacteristics(t *testing.T) {
+	for _, ch := range BihamShamir3Round {
+		if ch.Prob != 1.0/16 {
+			t.Errorf("p = %v, esperado 1/16\n%v", ch.Prob, ch)
+		}
+	}
+	if l, r := BihamShamir3Round[0].Output(); l != 0x04000000 || r != 0x40080000 {
+		t.Errorf("saída %08x %08x", l, r)
+	}
+}
+
+func TestSearchCharacteristic(t *testing.T) {
+	if ch := SearchCharacteristic(1, 1); ch.Prob != 1 {
+		t.Errorf("1 rodada: p = %v", ch.Prob)
+	}
+	ch := SearchCharacteristic(3, 1)
+	if ch.Prob != 1.0/16 {
+		t.Errorf("3 rodadas: p = %v\n%v", ch.Prob, ch)
+	}
+	if ch := SearchCharacteristic(4, 1); ch.Prob != 0 {
+		t.Errorf("4 rodadas com 1 S-box ativa: p = %v", ch.Prob)
+	}
+	// a característica encontrada tem que ser consistente com a DDT
+	var outs []uint32
+	for _, rd := range ch.Rounds {
+		outs = append(outs, rd.Out)
+	}
+	if again := NewCharacteristic(ch.L, ch.R, outs...); again.Prob != ch.Prob {
+		t.Errorf("probabilidade recalculada %v != %v", again.Prob, ch.Prob)
+	}
+}
+
+func TestFastDESWithRounds(t *testing.T) {
+	if _, err := NewFastDESWithRounds(fipsKey, 0); err == nil {
+		t.Error("esperado erro com 0 rodadas")
+	}
+	full, _ := NewFastDESWithRounds(fipsKey, NumRounds)
+	if got := full.EncryptUint64(toUint64(fipsPlain[:])); got != toUint64(fipsCiph[:]) {
+		t.Errorf("16 rodadas: %016x", got)
+	}
+	d, _ := NewFastDESWithRounds(fipsKey, 6)
+	if c := d.EncryptUint64(0x0123456789ABCDEF); d.DecryptUint64(c) != 0x0123456789ABCDEF {
+		t.Error("6 rodadas: decifragem não inverte")
+	}
+}
+
+func TestDifferentialAttack6Rounds(t *testing.T) {
+	rng := rand.New(rand.NewSource(6))
+	var key [KeySize]byte
+	rng.Read(key[:])
+
+	d, err := NewFastDESWithRounds(key, 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := DifferentialAttack(d.EncryptUint64, BihamShamir3Round, 200, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A contagem tem que acertar sozinha os 6 bits de cada S-box contada,
+	// sem depender da busca exaustiva que completa a chave depois
+	var mask uint64
+	for i, counted := range res.Counted {
+		if counted {
+			mask |= 0x3F << (42 - 6*i)
+		}
+	}
+	if mask == 0 {
+		t.Fatal("nenhuma S-box contada")
+	}
+	if want := d.subkeys[5] & mask; res.Voted != want {
+		t.Errorf("K6 votada = %012x, esperado %012x (máscara %012x)", res.Voted, want, mask)
+	}
+	if res.Subkey != d.subkeys[5] {
+		t.Errorf("K6 = %012x, esperado %012x", res.Subkey, d.subkeys[5])
+	}
+	if res.Key != FixParity(key) {
+		t.Errorf("chave %x, esperado %x", res.Key, FixParity(key))
+	}
+	if res.Trials > 1<<14 {
+		t.Errorf("%d chaves testadas", res.Trials)
+	}
+}
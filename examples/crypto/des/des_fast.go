@@ -0,0 +1,178 @@
+/*
+	DES rápido: SP-boxes e metades de 32 bits
+
+	A implementação de referência (des.go) trabalha bit a bit: permute,
+	getBit/setBit e slices alocados em toda rodada. Aqui as mesmas tabelas
+	viram pré-cálculos feitos uma única vez em init():
+
+		+--------------------+----------------------------------------------+
+		| Referência         | Versão rápida                                |
+		+--------------------+----------------------------------------------+
+		| permute(IP)        | ipTable[8][256]: 8 consultas + OR            |
+		| permute(IPInv)     | fpTable[8][256]: 8 consultas + OR            |
+		| permute(E)         | 8 rotações de 32 bits (cada S-box lê 6 bits  |
+		|                    | consecutivos de R, com volta circular)       |
+		| S-box + permute(P) | spBox[8][64]: saída da S-box já permutada    |
+		| subchave []byte    | uint64 com os 48 bits alinhados à direita    |
+		+--------------------+----------------------------------------------+
+
+	A função f fica:
+
+		f(R, K) = OR_i spBox[i][ ((R <<< (4i-1)) >> 26) XOR K_i ]
+
+	onde K_i são os 6 bits da subchave para a S-box i. Como P é uma
+	permutação, aplicá-la à saída de cada S-box separadamente e juntar com OR
+	dá o mesmo resultado que aplicá-la aos 32 bits de uma vez.
+
+	Nenhuma alocação por bloco. As consultas às tabelas dependem da chave,
+	então esta versão também vaza informação pelo cache, como o AES com
+	T-tables.
+*/
+
+package main
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+)
+
+var spBox [8][64]uint32
+var ipTable, fpTable [8][256]uint64
+
+func init() {
+	for i := 0; i < 8; i++ {
+		for x := 0; x < 64; x++ {
+			row := ((x & 0x20) >> 4) | (x & 0x01)
+			col := (x >> 1) & 0x0F
+			out := uint32(S[i][row*16+col]) << (28 - 4*i)
+			spBox[i][x] = permute32(out, P[:])
+		}
+	}
+	ipTable = permutationTable(IP[:])
+	fpTable = permutationTable(IPInv[:])
+}
+
+// permute32 aplica uma tabela 1-based de 32 entradas a um uint32 (bit 1 = MSB).
+func permute32(x uint32, table []uint8) uint32 {
+	var out uint32
+	for i, pos := range table {
+		out |= (x >> (32 - uint(pos)) & 1) << (31 - i)
+	}
+	return out
+}
+
+/*
+permutationTable: Pré-calcula uma permutação de 64 bits por byte de entrada.
+
+	t[j][v] = resultado da permutação de um bloco que só tem o byte j igual
+	a v (os outros zerados). Como a permutação só move bits, o resultado
+	para um bloco qualquer é o OR de t[j][byte j] para j = 0..7.
+*/
+func permutationTable(table []uint8) [8][256]uint64 {
+	var t [8][256]uint64
+	for j := 0; j < 8; j++ {
+		for v := 0; v < 256; v++ {
+			var out uint64
+			for i, pos := range table {
+				p := int(pos) - 1
+				if p/8 == j && (v>>(7-p%8))&1 == 1 {
+					out |= 1 << (63 - i)
+				}
+			}
+			t[j][v] = out
+		}
+	}
+	return t
+}
+
+func permute64(x uint64, t *[8][256]uint64) uint64 {
+	return t[0][x>>56] | t[1][x>>48&0xff] | t[2][x>>40&0xff] | t[3][x>>32&0xff] |
+		t[4][x>>24&0xff] | t[5][x>>16&0xff] | t[6][x>>8&0xff] | t[7][x&0xff]
+}
+
+// FastDES implementa cipher.Block com as tabelas pré-calculadas.
+type FastDES struct {
+	subkeys [NumRounds]uint64
+	rounds  int
+}
+
+var _ cipher.Block = (*FastDES)(nil)
+
+// NewFastDES gera as subchaves com o key schedule de referência e as
+// converte para uint64; aceita as mesmas verificações de New.
+func NewFastDES(key [KeySize]byte, checks ...KeyCheck) (*FastDES, error) {
+	ref, err := New(key, checks...)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &FastDES{rounds: NumRounds}
+	for i, k := range ref.subkeys {
+		f.subkeys[i] = toUint64(k[:])
+	}
+	return f, nil
+}
+
+/*
+NewFastDESWithRounds: Cria um DES reduzido, com apenas as `rounds`
+primeiras rodadas (1..16).
+
+	Usado na criptoanálise diferencial (des_differential.go). O key schedule
+	é o mesmo do DES completo; a saída continua sendo IPInv(Rn || Ln).
+*/
+func NewFastDESWithRounds(key [KeySize]byte, rounds int) (*FastDES, error) {
+	if rounds < 1 || rounds > NumRounds {
+		return nil, fmt.Errorf("des: número de rodadas inválido: %d (use 1 a %d)", rounds, NumRounds)
+	}
+	f, err := NewFastDES(key)
+	if err != nil {
+		return nil, err
+	}
+	f.rounds = rounds
+	return f, nil
+}
+
+func (d *FastDES) BlockSize() int { return BlockSize }
+
+func fastF(r uint32, k uint64) uint32 {
+	var out uint32
+	for i := 0; i < 8; i++ {
+		chunk := bits.RotateLeft32(r, 4*i-1) >> 26
+		out |= spBox[i][chunk^uint32(k>>(42-6*i))&0x3f]
+	}
+	return out
+}
+
+// cryptUint64 cifra (ou decifra) um bloco representado como uint64 big-endian.
+func (d *FastDES) cryptUint64(block uint64, decrypt bool) uint64 {
+	x := permute64(block, &ipTable)
+	l, r := uint32(x>>32), uint32(x)
+
+	for i := 0; i < d.rounds; i++ {
+		k := d.subkeys[i]
+		if decrypt {
+			k = d.subkeys[d.rounds-1-i]
+		}
+		l, r = r, l^fastF(r, k)
+	}
+
+	// R16 || L16
+	return permute64(uint64(r)<<32|uint64(l), &fpTable)
+}
+
+// EncryptUint64 e DecryptUint64 evitam a conversão de slices nos ataques.
+func (d *FastDES) EncryptUint64(block uint64) uint64 { return d.cryptUint64(block, false) }
+
+func (d *FastDES) DecryptUint64(block uint64) uint64 { return d.cryptUint64(block, true) }
+
+func (d *FastDES) Encrypt(dst, src []byte) {
+	loadBlock(dst, src)
+	binary.BigEndian.PutUint64(dst, d.cryptUint64(binary.BigEndian.Uint64(src), false))
+}
+
+func (d *FastDES) Decrypt(dst, src []byte) {
+	loadBlock(dst, src)
+	binary.BigEndian.PutUint64(dst, d.cryptUint64(binary.BigEndian.Uint64(src), true))
+}
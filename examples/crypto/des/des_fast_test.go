@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"crypto/des"
+	"math/rand"
+	"testing"
+)
+
+// A versão rápida deve produzir exatamente a mesma saída da referência.
+func TestFastDESIgualReferencia(t *testing.T) {
+	rng := rand.New(rand.NewSource(19))
+
+	for i := 0; i < 500; i++ {
+		var key, src [8]byte
+		rng.Read(key[:])
+		rng.Read(src[:])
+
+		ref, _ := New(key)
+		fast, err := NewFastDES(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got := make([]byte, BlockSize)
+		fast.Encrypt(got, src[:])
+		if want, _ := ref.Encrypt(src); !bytes.Equal(got, want[:]) {
+			t.Fatalf("Encrypt chave=%x bloco=%x: obtido %x, esperado %x", key, src, got, want)
+		}
+
+		fast.Decrypt(got, src[:])
+		if want, _ := ref.Decrypt(src); !bytes.Equal(got, want[:]) {
+			t.Fatalf("Decrypt chave=%x bloco=%x: obtido %x, esperado %x", key, src, got, want)
+		}
+	}
+}
+
+func TestFastDESFIPS(t *testing.T) {
+	fast, _ := NewFastDES(fipsKey)
+	got := make([]byte, BlockSize)
+	fast.Encrypt(got, fipsPlain[:])
+	if !bytes.Equal(got, fipsCiph[:]) {
+		t.Errorf("cifrado %x, esperado %x", got, fipsCiph)
+	}
+}
+
+func TestFastDESSemAlocacao(t *testing.T) {
+	fast, _ := NewFastDES(fipsKey)
+	buf := make([]byte, BlockSize)
+	if n := testing.AllocsPerRun(100, func() { fast.Encrypt(buf, buf) }); n != 0 {
+		t.Errorf("Encrypt alocou %.0f vezes por bloco", n)
+	}
+}
+
+func benchmarkEncrypt(b *testing.B, encrypt func(dst, src []byte)) {
+	buf := make([]byte, BlockSize)
+	b.SetBytes(BlockSize)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		encrypt(buf, buf)
+	}
+}
+
+func BenchmarkEncryptReferencia(b *testing.B) {
+	d, _ := New(fipsKey)
+	benchmarkEncrypt(b, func(dst, src []byte) {
+		out, _ := d.Encrypt([8]byte(src))
+		copy(dst, out[:])
+	})
+}
+
+func BenchmarkEncryptFast(b *testing.B) {
+	d, _ := NewFastDES(fipsKey)
+	benchmarkEncrypt(b, d.Encrypt)
+}
+
+func BenchmarkEncryptCryptoDES(b *testing.B) {
+	d, _ := des.NewCipher(fipsKey[:])
+	benchmarkEncrypt(b, d.Encrypt)
+}
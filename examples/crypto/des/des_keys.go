@@ -0,0 +1,180 @@
+/*
+	Higiene de chaves do DES
+
+	Paridade: cada byte da chave tem 7 bits de chave e 1 bit de paridade (o
+	menos significativo), escolhido para que o byte tenha um número ÍMPAR de
+	bits 1. PC1 descarta esses bits, então a paridade não afeta a cifra; ela
+	só serve para detectar chaves corrompidas.
+
+	Chaves fracas: PC1 divide a chave em C e D (28 bits) que só são
+	rotacionados. Se C e D forem periódicos, as rotações repetem valores e o
+	DES passa a ter poucas subchaves distintas:
+
+		+-----------------+--------+------------------------+---------------------+
+		| Classe          | Chaves | Subchaves distintas    | Consequência        |
+		+-----------------+--------+------------------------+---------------------+
+		| fraca           |    4   | 1 (C e D constantes)   | E_K(E_K(x)) = x     |
+		| semifraca       |   12   | 2 (período 1 ou 2)     | E_K1(E_K2(x)) = x   |
+		| possivelmente   |  240   | 4 (período 4)          | ciclos curtos       |
+		| fraca           |        |                        |                     |
+		+-----------------+--------+------------------------+---------------------+
+
+	Há 16 metades de período 4 (os padrões de 4 bits repetidos 7 vezes), o
+	que dá 16 x 16 = 256 chaves efetivas: 4 fracas, 12 semifracas e 240
+	possivelmente fracas. A lista de 48 da literatura (Schneier, tabela
+	12.13) só combina os padrões 0, 1, 01, 10, 0011, 0110, 1100 e 1001;
+	chaves como fe1f1f1ffe0e0e0e, com D = 0001 repetido, também têm só 4
+	subchaves distintas e entram na contagem.
+
+	Como as rotações acumuladas percorrem todos os restos módulo 7, uma
+	metade de período 7, 14 ou 28 assume pelo menos 7 valores ao longo das
+	rodadas. Por isso a classificação é feita contando as subchaves, sem
+	tabela fixa; WeakKeys e SemiWeakKeys listam as chaves com paridade
+	correta para referência.
+*/
+
+package main
+
+import "fmt"
+
+// KeyCheck seleciona as verificações feitas por New.
+type KeyCheck int
+
+const (
+	CheckParity       KeyCheck = 1 << iota // paridade ímpar em todos os bytes
+	CheckWeak                              // rejeita chaves fracas e semifracas
+	CheckPossiblyWeak                      // rejeita também as possivelmente fracas
+)
+
+// WeakKind é a classe de uma chave fraca.
+type WeakKind int
+
+const (
+	NotWeak WeakKind = iota
+	Weak
+	SemiWeak
+	PossiblyWeak
+)
+
+func (k WeakKind) String() string {
+	switch k {
+	case NotWeak:
+		return "não fraca"
+	case Weak:
+		return "fraca"
+	case SemiWeak:
+		return "semifraca"
+	case PossiblyWeak:
+		return "possivelmente fraca"
+	default:
+		return fmt.Sprintf("WeakKind(%d)", int(k))
+	}
+}
+
+// ParityError indica o primeiro byte da chave sem paridade ímpar.
+type ParityError struct {
+	Key  [KeySize]byte
+	Byte int
+}
+
+func (e *ParityError) Error() string {
+	return fmt.Sprintf("des: byte %d da chave %x não tem paridade ímpar", e.Byte, e.Key)
+}
+
+// WeakKeyError indica uma chave fraca, semifraca ou possivelmente fraca.
+type WeakKeyError struct {
+	Key  [KeySize]byte
+	Kind WeakKind
+}
+
+func (e *WeakKeyError) Error() string {
+	return fmt.Sprintf("des: chave %x é %s", e.Key, e.Kind)
+}
+
+// As 4 chaves fracas (com paridade ímpar).
+var WeakKeys = [4][KeySize]byte{
+	{0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01},
+	{0xFE, 0xFE, 0xFE, 0xFE, 0xFE, 0xFE, 0xFE, 0xFE},
+	{0xE0, 0xE0, 0xE0, 0xE0, 0xF1, 0xF1, 0xF1, 0xF1},
+	{0x1F, 0x1F, 0x1F, 0x1F, 0x0E, 0x0E, 0x0E, 0x0E},
+}
+
+// Os 6 pares de chaves semifracas: E_K1(E_K2(x)) = x para cada par.
+var SemiWeakKeys = [6][2][KeySize]byte{
+	{{0x01, 0xFE, 0x01, 0xFE, 0x01, 0xFE, 0x01, 0xFE}, {0xFE, 0x01, 0xFE, 0x01, 0xFE, 0x01, 0xFE, 0x01}},
+	{{0x1F, 0xE0, 0x1F, 0xE0, 0x0E, 0xF1, 0x0E, 0xF1}, {0xE0, 0x1F, 0xE0, 0x1F, 0xF1, 0x0E, 0xF1, 0x0E}},
+	{{0x01, 0xE0, 0x01, 0xE0, 0x01, 0xF1, 0x01, 0xF1}, {0xE0, 0x01, 0xE0, 0x01, 0xF1, 0x01, 0xF1, 0x01}},
+	{{0x1F, 0xFE, 0x1F, 0xFE, 0x0E, 0xFE, 0x0E, 0xFE}, {0xFE, 0x1F, 0xFE, 0x1F, 0xFE, 0x0E, 0xFE, 0x0E}},
+	{{0x01, 0x1F, 0x01, 0x1F, 0x01, 0x0E, 0x01, 0x0E}, {0x1F, 0x01, 0x1F, 0x01, 0x0E, 0x01, 0x0E, 0x01}},
+	{{0xE0, 0xFE, 0xE0, 0xFE, 0xF1, 0xFE, 0xF1, 0xFE}, {0xFE, 0xE0, 0xFE, 0xE0, 0xFE, 0xF1, 0xFE, 0xF1}},
+}
+
+// CheckKeyParity devolve *ParityError se algum byte não tiver paridade ímpar.
+func CheckKeyParity(key [KeySize]byte) error {
+	for i, b := range key {
+		if !oddParity(b) {
+			return &ParityError{Key: key, Byte: i}
+		}
+	}
+	return nil
+}
+
+// FixParity ajusta o bit menos significativo de cada byte para paridade ímpar.
+func FixParity(key [KeySize]byte) [KeySize]byte {
+	for i, b := range key {
+		if !oddParity(b) {
+			key[i] = b ^ 1
+		}
+	}
+	return key
+}
+
+func oddParity(b byte) bool {
+	b ^= b >> 4
+	b ^= b >> 2
+	b ^= b >> 1
+	return b&1 == 1
+}
+
+// ClassifyKey diz se a chave é fraca, semifraca ou possivelmente fraca.
+func ClassifyKey(key [KeySize]byte) WeakKind {
+	d := &DES{}
+	d.generateSubkeys(key)
+	return d.weakKind()
+}
+
+// weakKind conta as subchaves distintas (ver comentário do arquivo).
+func (d *DES) weakKind() WeakKind {
+	distinct := make(map[[6]byte]bool)
+	for _, k := range d.subkeys {
+		distinct[k] = true
+	}
+
+	switch n := len(distinct); {
+	case n == 1:
+		return Weak
+	case n == 2:
+		return SemiWeak
+	case n <= 4:
+		return PossiblyWeak
+	default:
+		return NotWeak
+	}
+}
+
+func (d *DES) checkKey(key [KeySize]byte, checks KeyCheck) error {
+	if checks&CheckParity != 0 {
+		if err := CheckKeyParity(key); err != nil {
+			return err
+		}
+	}
+	if checks&(CheckWeak|CheckPossiblyWeak) == 0 {
+		return nil
+	}
+
+	kind := d.weakKind()
+	if kind == Weak || kind == SemiWeak || (kind == PossiblyWeak && checks&CheckPossiblyWeak != 0) {
+		return &WeakKeyError{Key: key, Kind: kind}
+	}
+	return nil
+}
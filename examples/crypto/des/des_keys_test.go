@@ -0,0 +1,152 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestChavesFracas(t *testing.T) {
+	x := [8]byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xAB, 0xCD, 0xEF}
+
+	for _, key := range WeakKeys {
+		if err := CheckKeyParity(key); err != nil {
+			t.Errorf("%x: %v", key, err)
+		}
+		if kind := ClassifyKey(key); kind != Weak {
+			t.Errorf("%x classificada como %s", key, kind)
+		}
+		// Cifrar duas vezes com uma chave fraca devolve o texto original
+		d, _ := New(key)
+		c, _ := d.Encrypt(x)
+		if p, _ := d.Encrypt(c); p != x {
+			t.Errorf("%x: E(E(x)) = %x", key, p)
+		}
+	}
+
+	for _, pair := range SemiWeakKeys {
+		for _, key := range pair {
+			if kind := ClassifyKey(key); kind != SemiWeak {
+				t.Errorf("%x classificada como %s", key, kind)
+			}
+		}
+		d1, _ := New(pair[0])
+		d2, _ := New(pair[1])
+		c, _ := d2.Encrypt(x)
+		if p, _ := d1.Encrypt(c); p != x {
+			t.Errorf("%x / %x: E_K1(E_K2(x)) = %x", pair[0], pair[1], p)
+		}
+	}
+
+	// Schneier, Applied Cryptography, tabela 12.13
+	if kind := ClassifyKey([8]byte{0x1F, 0x1F, 0x01, 0x01, 0x0E, 0x0E, 0x01, 0x01}); kind != PossiblyWeak {
+		t.Errorf("1f1f01010e0e0101 classificada como %s", kind)
+	}
+	if kind := ClassifyKey([8]byte{0x13, 0x34, 0x57, 0x79, 0x9B, 0xBC, 0xDF, 0xF1}); kind != NotWeak {
+		t.Errorf("chave do FIPS classificada como %s", kind)
+	}
+}
+
+// periodicHalf repete o padrão p nos 28 bits de uma metade C ou D.
+func periodicHalf(p string) []byte {
+	bits := make([]byte, 28)
+	for i := range bits {
+		bits[i] = p[i%len(p)] - '0'
+	}
+	return bits
+}
+
+// keyFromHalves desfaz PC1: o bit i de C || D vem do bit PC1[i] da chave.
+func keyFromHalves(c, d []byte) [8]byte {
+	cd := append(append([]byte{}, c...), d...)
+	var key [8]byte
+	for i, pos := range PC1 {
+		setBit(key[:], int(pos)-1, cd[i])
+	}
+	return FixParity(key)
+}
+
+func countWeakKeys(patterns []string) map[WeakKind]int {
+	counts := map[WeakKind]int{}
+	for _, pc := range patterns {
+		for _, pd := range patterns {
+			counts[ClassifyKey(keyFromHalves(periodicHalf(pc), periodicHalf(pd)))]++
+		}
+	}
+	return counts
+}
+
+func TestContagemDeChavesFracas(t *testing.T) {
+	// Todas as 16 metades de período que divide 4
+	var all []string
+	for p := 0; p < 16; p++ {
+		all = append(all, fmt.Sprintf("%04b", p))
+	}
+	counts := countWeakKeys(all)
+	want := map[WeakKind]int{Weak: 4, SemiWeak: 12, PossiblyWeak: 240}
+	for kind, n := range want {
+		if counts[kind] != n {
+			t.Errorf("%d chaves %s, esperado %d", counts[kind], kind, n)
+		}
+	}
+
+	// Os padrões da lista de 48 chaves possivelmente fracas da literatura
+	classic := countWeakKeys([]string{"0000", "1111", "0101", "1010", "0011", "0110", "1100", "1001"})
+	if classic[PossiblyWeak] != 48 {
+		t.Errorf("%d chaves possivelmente fracas na lista clássica, esperado 48", classic[PossiblyWeak])
+	}
+
+	// Fora da lista clássica, mas com só 4 subchaves distintas
+	if kind := ClassifyKey([8]byte{0xFE, 0x1F, 0x1F, 0x1F, 0xFE, 0x0E, 0x0E, 0x0E}); kind != PossiblyWeak {
+		t.Errorf("fe1f1f1ffe0e0e0e classificada como %s", kind)
+	}
+}
+
+func TestFixParity(t *testing.T) {
+	key := [8]byte{0x12, 0x34, 0x56, 0x78, 0x9A, 0xBC, 0xDE, 0xF0}
+	fixed := FixParity(key)
+	if err := CheckKeyParity(fixed); err != nil {
+		t.Fatal(err)
+	}
+	if want := [8]byte{0x13, 0x34, 0x57, 0x79, 0x9B, 0xBC, 0xDF, 0xF1}; fixed != want {
+		t.Errorf("FixParity = %x, esperado %x", fixed, want)
+	}
+
+	// A paridade não muda as subchaves
+	d1, _ := New(key)
+	d2, _ := New(fixed)
+	if d1.subkeys != d2.subkeys {
+		t.Error("subchaves mudaram ao corrigir a paridade")
+	}
+}
+
+func TestNewVerificacoes(t *testing.T) {
+	var parityErr *ParityError
+	if _, err := New([8]byte{0x12, 0x34, 0x56, 0x78, 0x9A, 0xBC, 0xDE, 0xF0}, CheckParity); !errors.As(err, &parityErr) || parityErr.Byte != 0 {
+		t.Errorf("paridade: err = %v", err)
+	}
+
+	var weakErr *WeakKeyError
+	if _, err := New(WeakKeys[0], CheckParity, CheckWeak); !errors.As(err, &weakErr) || weakErr.Kind != Weak {
+		t.Errorf("fraca: err = %v", err)
+	}
+	if _, err := New(SemiWeakKeys[2][1], CheckWeak); !errors.As(err, &weakErr) || weakErr.Kind != SemiWeak {
+		t.Errorf("semifraca: err = %v", err)
+	}
+
+	possibly := [8]byte{0x1F, 0x1F, 0x01, 0x01, 0x0E, 0x0E, 0x01, 0x01}
+	if _, err := New(possibly, CheckWeak); err != nil {
+		t.Errorf("possivelmente fraca com CheckWeak: err = %v", err)
+	}
+	if _, err := New(possibly, CheckPossiblyWeak); !errors.As(err, &weakErr) || weakErr.Kind != PossiblyWeak {
+		t.Errorf("possivelmente fraca: err = %v", err)
+	}
+
+	// Sem verificações qualquer chave é aceita
+	if _, err := New(WeakKeys[1]); err != nil {
+		t.Errorf("sem verificações: err = %v", err)
+	}
+	if _, err := New(fipsKey, CheckParity, CheckPossiblyWeak); err != nil {
+		t.Errorf("chave do FIPS: err = %v", err)
+	}
+}
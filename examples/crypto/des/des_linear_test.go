@@ -0,0 +1,126 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestDESLAT(t *testing.T) {
+	// Aproximação de Matsui em S5: NS5(16, 15) = 12
+	if got := sboxLAT[4][0x10][0xF]; got != -20 {
+		t.Errorf("LAT de S5[10][F] = %d, esperado -20", got)
+	}
+	for i := 0; i < 8; i++ {
+		if sboxLAT[i][0][0] != 32 {
+			t.Errorf("S%d: LAT[0][0] = %d", i+1, sboxLAT[i][0][0])
+		}
+		// Cada bit de saída é balanceado: LAT[0][b] = 0 para b != 0
+		for b := 1; b < 16; b++ {
+			if sboxLAT[i][0][b] != 0 {
+				t.Errorf("S%d: LAT[0][%x] = %d", i+1, b, sboxLAT[i][0][b])
+			}
+		}
+	}
+}
+
+func TestSearchLinearApproximation(t *testing.T) {
+	// Biases da tabela de Matsui (1993)
+	want := map[int]float64{
+		1: 0.5,
+		3: 2 * (20.0 / 64) * (20.0 / 64),                // 1.56 * 2^-3
+		4: -4 * (20.0 / 64) * (20.0 / 64) * (10.0 / 64), // -1.95 * 2^-5
+	}
+	for rounds, bias := range want {
+		a := SearchLinearApproximation(rounds, 1)
+		if math.Abs(a.Bias-bias) > 1e-12 {
+			t.Errorf("%d rodadas: ε = %v, esperado %v\n%v", rounds, a.Bias, bias, a)
+		}
+	}
+
+	// Com 2 S-boxes ativas por rodada o ótimo de 4 rodadas não muda
+	if a := SearchLinearApproximation(4, 2); math.Abs(math.Abs(a.Bias)-math.Abs(want[4])) > 1e-12 {
+		t.Errorf("4 rodadas com maxActive = 2: ε = %v\n%v", a.Bias, a)
+	}
+}
+
+// O bias medido com DES de verdade tem que bater com o do lema do empilhamento.
+func TestLinearApproximationBias(t *testing.T) {
+	a := SearchLinearApproximation(4, 1)
+	pl, pr, cl, cr := a.Masks()
+
+	rng := rand.New(rand.NewSource(4))
+	var key [KeySize]byte
+	rng.Read(key[:])
+	d, _ := NewFastDESWithRounds(key, 4)
+	kp := a.KeyParity(d.subkeys[:4])
+
+	const n = 100000
+	hits := 0
+	for _, p := range RandomKnownPairs(d, n, rng) {
+		x, y := afterIP(p)
+		if linearSide(x, y, pl, pr, cl, cr) == kp {
+			hits++
+		}
+	}
+	if got := float64(hits)/n - 0.5; math.Abs(got-a.Bias) > 0.01 {
+		t.Errorf("bias medido %v, previsto %v", got, a.Bias)
+	}
+}
+
+func TestMatsuiAlgorithm1(t *testing.T) {
+	a := SearchLinearApproximation(4, 1)
+	rng := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 5; trial++ {
+		var key [KeySize]byte
+		rng.Read(key[:])
+		d, _ := NewFastDESWithRounds(key, 4)
+
+		bit, _ := MatsuiAlgorithm1(a, RandomKnownPairs(d, 5000, rng))
+		if want := a.KeyParity(d.subkeys[:4]); bit != want {
+			t.Errorf("chave %x: bit %d, esperado %d", key, bit, want)
+		}
+	}
+}
+
+func TestMatsuiAlgorithm2(t *testing.T) {
+	a := SearchAlgorithm2Approximation(3, 1)
+	rng := rand.New(rand.NewSource(2))
+	var key [KeySize]byte
+	rng.Read(key[:])
+	d, _ := NewFastDESWithRounds(key, 5)
+
+	res, err := MatsuiAlgorithm2(a, RandomKnownPairs(d, 2000, rng))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.FirstMask == 0 || res.LastMask == 0 {
+		t.Fatalf("máscaras vazias: %012x %012x", res.FirstMask, res.LastMask)
+	}
+	if got, want := res.First, d.subkeys[0]&res.FirstMask; got != want {
+		t.Errorf("K1: %012x, esperado %012x", got, want)
+	}
+	if got, want := res.Last, d.subkeys[4]&res.LastMask; got != want {
+		t.Errorf("K5: %012x, esperado %012x", got, want)
+	}
+	if want := a.KeyParity(d.subkeys[1:4]); res.Parity != want {
+		t.Errorf("paridade %d, esperado %d", res.Parity, want)
+	}
+}
+
+func TestLinearSuccessRate(t *testing.T) {
+	rates, err := LinearSuccessRate(1, 4, []int{8, 4000}, 20, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rates[1].Rate() < 0.95 {
+		t.Errorf("4000 plaintexts: taxa %v", rates[1].Rate())
+	}
+	if rates[0].Rate() >= rates[1].Rate() {
+		t.Errorf("taxa não cresce com N: %v", rates)
+	}
+
+	if _, err := LinearSuccessRate(3, 4, nil, 1, 1); err == nil {
+		t.Error("esperado erro com algoritmo 3")
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMeetInTheMiddleSDES(t *testing.T) {
+	ks := SDESKeySpace()
+	k1, k2 := uint64(0b1010000010), uint64(0b0111010011)
+
+	var pairs []KnownPair
+	for _, p := range []uint64{0x00, 0x3C, 0x97, 0xF1, 0x5A} {
+		pairs = append(pairs, KnownPair{p, DoubleEncrypt(ks, k1, k2, p)})
+	}
+
+	r, err := MeetInTheMiddle(ks, pairs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(r.Keys, [2]uint64{k1, k2}) {
+		t.Fatalf("chaves %x não contêm (%x, %x)", r.Keys, k1, k2)
+	}
+	if r.Operations != 2*1024 || r.BruteForce != 1024*1024 {
+		t.Errorf("operações %d, força bruta %d", r.Operations, r.BruteForce)
+	}
+	// Blocos de 8 bits e chaves de 20 bits: o primeiro par deixa ~2^12 encontros
+	if r.Candidates < 1<<11 {
+		t.Errorf("só %d candidatos no meio", r.Candidates)
+	}
+}
+
+func TestMeetInTheMiddleDESReduzido(t *testing.T) {
+	ks := ReducedDESKeySpace(fipsKey, 10)
+	k1, k2 := uint64(0x2F1), uint64(0x0A7)
+	pairs := []KnownPair{
+		{0x0123456789ABCDEF, DoubleEncrypt(ks, k1, k2, 0x0123456789ABCDEF)},
+		{0x1111111111111111, DoubleEncrypt(ks, k1, k2, 0x1111111111111111)},
+	}
+
+	seq, err := MeetInTheMiddle(ks, pairs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	par, err := MeetInTheMiddleParallel(ks, pairs, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][2]uint64{{k1, k2}}
+	if !slices.Equal(seq.Keys, want) || !slices.Equal(par.Keys, want) {
+		t.Errorf("sequencial %x, paralelo %x, esperado %x", seq.Keys, par.Keys, want)
+	}
+	if par.Workers != 4 || seq.Workers != 1 {
+		t.Errorf("workers: %d e %d", seq.Workers, par.Workers)
+	}
+}
+
+func TestReducedDESKey(t *testing.T) {
+	ks := ReducedDESKeySpace(fipsKey, 14)
+	positions := []int{}
+	for pos := 63; len(positions) < 14; pos-- {
+		if pos%8 != 7 {
+			positions = append(positions, pos)
+		}
+	}
+
+	// Os bits desconhecidos nunca caem nos bits de paridade
+	key := ReducedDESKey(fipsKey, positions, 1<<14-1)
+	for i := range key {
+		if key[i]&1 != fipsKey[i]&1 {
+			t.Errorf("bit de paridade do byte %d mudou", i)
+		}
+	}
+	if ks.Size() != 1<<14 {
+		t.Errorf("Size = %d", ks.Size())
+	}
+}
+
+func TestMeetInTheMiddleErros(t *testing.T) {
+	if _, err := MeetInTheMiddle(SDESKeySpace(), nil); err == nil {
+		t.Error("esperado erro sem pares")
+	}
+	for _, bits := range []int{MaxMITMBits + 1, 32} {
+		if _, err := MeetInTheMiddle(ReducedDESKeySpace(fipsKey, bits), []KnownPair{{}}); err == nil {
+			t.Errorf("esperado erro com %d bits", bits)
+		}
+	}
+}
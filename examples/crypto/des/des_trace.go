@@ -0,0 +1,208 @@
+/*
+	Rastreamento do DES
+
+	Encrypt e Decrypt não imprimem nada. Para acompanhar a cifra passo a
+	passo use EncryptTrace/DecryptTrace com um Observer, que recebe um
+	TraceEvent por etapa:
+
+		+---------+--------+-------------------------+--------------------------+
+		| Kind    | Round  | In                      | Out                      |
+		+---------+--------+-------------------------+--------------------------+
+		| ip      | 0      | bloco de entrada (64)   | saída de IP (64)         |
+		| subkey  | 1..16  | -                       | subchave da rodada (48)  |
+		| sbox    | 1..16  | 6 bits da S-box SBox    | 4 bits de saída          |
+		| f       | 1..16  | R anterior (32)         | f(R, K) (32)             |
+		| round   | 1..16  | L da rodada (32)        | R da rodada (32)         |
+		| output  | 0      | R16 || L16 (64)         | saída de IPInv (64)      |
+		+---------+--------+-------------------------+--------------------------+
+
+	Sinks prontos:
+
+		- TextSink: uma linha por evento, no formato do passo a passo de des-go.md
+		- CSVSink: kind,round,sbox,in,out (valores em hexadecimal)
+		- TraceRecorder: guarda os eventos em memória e exporta JSON
+
+	Gerar a saída do passo a passo:
+
+		go run . trace         # texto
+		go run . trace json
+		go run . trace csv
+*/
+
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Tipos de evento enviados ao Observer.
+const (
+	EventIP     = "ip"
+	EventSubkey = "subkey"
+	EventSBox   = "sbox"
+	EventF      = "f"
+	EventRound  = "round"
+	EventOutput = "output"
+)
+
+// TraceEvent descreve uma etapa da cifra. In e Out guardam os valores
+// alinhados à direita, com InBits e OutBits bits significativos.
+type TraceEvent struct {
+	Kind    string
+	Round   int
+	SBox    int
+	In      uint64
+	InBits  int
+	Out     uint64
+	OutBits int
+}
+
+// Observer recebe as etapas de EncryptTrace e DecryptTrace.
+type Observer interface {
+	Observe(e TraceEvent)
+}
+
+func emit(obs Observer, e TraceEvent) {
+	if obs != nil {
+		obs.Observe(e)
+	}
+}
+
+// toUint64 junta até 8 bytes (MSB primeiro) em um inteiro.
+func toUint64(b []byte) uint64 {
+	var v uint64
+	for _, x := range b {
+		v = v<<8 | uint64(x)
+	}
+	return v
+}
+
+// hexValue formata v com o número de dígitos hexadecimais de bits bits.
+func hexValue(v uint64, bits int) string {
+	if bits == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%0*x", (bits+3)/4, v)
+}
+
+// TextSink escreve cada evento como uma linha de texto.
+type TextSink struct {
+	W   io.Writer
+	Err error
+}
+
+func (s *TextSink) Observe(e TraceEvent) {
+	if s.Err != nil {
+		return
+	}
+
+	var line string
+	switch e.Kind {
+	case EventIP:
+		line = fmt.Sprintf("Entrada:            %s\nApós IP:            %s\n", hexValue(e.In, e.InBits), hexValue(e.Out, e.OutBits))
+	case EventSubkey:
+		line = fmt.Sprintf("Rodada %2d  K:      %s\n", e.Round, hexValue(e.Out, e.OutBits))
+	case EventSBox:
+		line = fmt.Sprintf("Rodada %2d  S%d:     %06b -> %04b\n", e.Round, e.SBox, e.In, e.Out)
+	case EventF:
+		line = fmt.Sprintf("Rodada %2d  f(R,K): %s -> %s\n", e.Round, hexValue(e.In, e.InBits), hexValue(e.Out, e.OutBits))
+	case EventRound:
+		line = fmt.Sprintf("Rodada %2d  L R:    %s %s\n", e.Round, hexValue(e.In, e.InBits), hexValue(e.Out, e.OutBits))
+	case EventOutput:
+		line = fmt.Sprintf("R16 || L16:         %s\nSaída:              %s\n", hexValue(e.In, e.InBits), hexValue(e.Out, e.OutBits))
+	}
+	_, s.Err = io.WriteString(s.W, line)
+}
+
+// CSVSink escreve os eventos como CSV; chame Flush no final.
+type CSVSink struct {
+	w      *csv.Writer
+	header bool
+}
+
+func NewCSVSink(w io.Writer) *CSVSink {
+	return &CSVSink{w: csv.NewWriter(w)}
+}
+
+func (s *CSVSink) Observe(e TraceEvent) {
+	if !s.header {
+		s.w.Write([]string{"kind", "round", "sbox", "in", "out"})
+		s.header = true
+	}
+	s.w.Write([]string{
+		e.Kind,
+		strconv.Itoa(e.Round),
+		strconv.Itoa(e.SBox),
+		hexValue(e.In, e.InBits),
+		hexValue(e.Out, e.OutBits),
+	})
+}
+
+// Flush grava o que estiver em buffer e devolve o primeiro erro de escrita.
+func (s *CSVSink) Flush() error {
+	s.w.Flush()
+	return s.w.Error()
+}
+
+// TraceRecorder guarda os eventos em memória.
+type TraceRecorder struct {
+	Events []TraceEvent
+}
+
+func (r *TraceRecorder) Observe(e TraceEvent) {
+	r.Events = append(r.Events, e)
+}
+
+// MarshalJSON exporta os valores em hexadecimal, sem os campos vazios.
+func (e TraceEvent) MarshalJSON() ([]byte, error) {
+	type jsonEvent struct {
+		Kind  string `json:"kind"`
+		Round int    `json:"round,omitempty"`
+		SBox  int    `json:"sbox,omitempty"`
+		In    string `json:"in,omitempty"`
+		Out   string `json:"out,omitempty"`
+	}
+	return json.Marshal(jsonEvent{e.Kind, e.Round, e.SBox, hexValue(e.In, e.InBits), hexValue(e.Out, e.OutBits)})
+}
+
+// JSON devolve os eventos gravados como um array JSON indentado.
+func (r *TraceRecorder) JSON() ([]byte, error) {
+	return json.MarshalIndent(r.Events, "", "  ")
+}
+
+// runTrace cifra o vetor do FIPS e imprime o passo a passo: go run . trace [json|csv]
+func runTrace(w io.Writer, format string) error {
+	key := [8]byte{0x13, 0x34, 0x57, 0x79, 0x9B, 0xBC, 0xDF, 0xF1}
+	block := [8]byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xAB, 0xCD, 0xEF}
+
+	d, err := New(key)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case "", "text":
+		sink := &TextSink{W: w}
+		d.EncryptTrace(block, sink)
+		return sink.Err
+	case "csv":
+		sink := NewCSVSink(w)
+		d.EncryptTrace(block, sink)
+		return sink.Flush()
+	case "json":
+		rec := &TraceRecorder{}
+		d.EncryptTrace(block, rec)
+		out, err := rec.JSON()
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", out)
+		return err
+	default:
+		return fmt.Errorf("formato desconhecido %q (use text, json ou csv)", format)
+	}
+}
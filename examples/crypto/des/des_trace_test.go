@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+var (
+	fipsKey   = [8]byte{0x13, 0x34, 0x57, 0x79, 0x9B, 0xBC, 0xDF, 0xF1}
+	fipsPlain = [8]byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xAB, 0xCD, 0xEF}
+	fipsCiph  = [8]byte{0x85, 0xE8, 0x13, 0x54, 0x0F, 0x0A, 0xB4, 0x05}
+)
+
+func TestEncryptSemSaida(t *testing.T) {
+	d, err := New(fipsKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	got, _ := d.Encrypt(fipsPlain)
+	back, _ := d.Decrypt(got)
+	os.Stdout = stdout
+	w.Close()
+
+	printed, _ := io.ReadAll(r)
+	if len(printed) != 0 {
+		t.Errorf("Encrypt/Decrypt escreveram no stdout: %q", printed)
+	}
+	if got != fipsCiph || back != fipsPlain {
+		t.Errorf("cifrado %x, decifrado %x", got, back)
+	}
+}
+
+func TestEncryptTraceEventos(t *testing.T) {
+	d, _ := New(fipsKey)
+	rec := &TraceRecorder{}
+	got, _ := d.EncryptTrace(fipsPlain, rec)
+	if got != fipsCiph {
+		t.Fatalf("cifrado %x, esperado %x", got, fipsCiph)
+	}
+
+	// ip + 16 * (subchave + 8 S-boxes + f + rodada) + output
+	if want := 1 + 16*11 + 1; len(rec.Events) != want {
+		t.Fatalf("%d eventos, esperado %d", len(rec.Events), want)
+	}
+
+	// Valores do passo a passo clássico (J. Orlin Grabbe, "The DES Algorithm Illustrated")
+	find := func(kind string, round int) TraceEvent {
+		for _, e := range rec.Events {
+			if e.Kind == kind && e.Round == round {
+				return e
+			}
+		}
+		t.Fatalf("evento %s da rodada %d não encontrado", kind, round)
+		return TraceEvent{}
+	}
+	checks := []struct {
+		kind  string
+		round int
+		value uint64
+	}{
+		{EventIP, 0, 0xcc00ccfff0aaf0aa},
+		{EventSubkey, 1, 0x1b02effc7072},
+		{EventF, 1, 0x234aa9bb},
+		{EventRound, 1, 0xef4a6544},
+		{EventSubkey, 16, 0xcb3d8b0e17f5},
+		{EventOutput, 0, 0x85e813540f0ab405},
+	}
+	for _, c := range checks {
+		if e := find(c.kind, c.round); e.Out != c.value {
+			t.Errorf("%s rodada %d: %x, esperado %x", c.kind, c.round, e.Out, c.value)
+		}
+	}
+}
+
+func TestDecryptTrace(t *testing.T) {
+	d, _ := New(fipsKey)
+	rec := &TraceRecorder{}
+	got, _ := d.DecryptTrace(fipsCiph, rec)
+	if got != fipsPlain {
+		t.Fatalf("decifrado %x, esperado %x", got, fipsPlain)
+	}
+	// Na decifragem a primeira subchave usada é K16
+	if e := rec.Events[1]; e.Kind != EventSubkey || e.Out != 0xcb3d8b0e17f5 {
+		t.Errorf("primeira subchave %x", e.Out)
+	}
+}
+
+func TestSinks(t *testing.T) {
+	var text, csv, js bytes.Buffer
+	for format, buf := range map[string]*bytes.Buffer{"text": &text, "csv": &csv, "json": &js} {
+		if err := runTrace(buf, format); err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+	}
+
+	if !strings.Contains(text.String(), "Rodada  1  f(R,K): f0aaf0aa -> 234aa9bb") {
+		t.Errorf("texto sem a linha de f da rodada 1:\n%s", text.String())
+	}
+
+	lines := strings.Split(strings.TrimSpace(csv.String()), "\n")
+	if len(lines) != 179 || lines[0] != "kind,round,sbox,in,out" {
+		t.Errorf("CSV com %d linhas, cabeçalho %q", len(lines), lines[0])
+	}
+	if lines[len(lines)-1] != "output,0,0,0a4cd99543423234,85e813540f0ab405" {
+		t.Errorf("última linha do CSV: %q", lines[len(lines)-1])
+	}
+
+	var events []map[string]any
+	if err := json.Unmarshal(js.Bytes(), &events); err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 178 || events[3]["sbox"] != float64(2) {
+		t.Errorf("JSON com %d eventos, evento 3 = %v", len(events), events[3])
+	}
+
+	if err := runTrace(io.Discard, "xml"); err == nil {
+		t.Error("esperado erro para formato desconhecido")
+	}
+}
@@ -0,0 +1,186 @@
+/*
+	Triple DES (TDEA) e DESX
+
+	A chave de 56 bits do DES é o seu ponto fraco. As duas extensões abaixo
+	reaproveitam o DES sem mudar nada dentro dele:
+
+	3DES (NIST SP 800-67), formato EDE:
+
+		C = E_K3(D_K2(E_K1(P)))        P = D_K1(E_K2(D_K3(C)))
+
+		+-------+------------------+-------------+--------------------------+
+		| Opção | Chaves           | Tamanho     | Observação               |
+		+-------+------------------+-------------+--------------------------+
+		|   1   | K1, K2, K3       | 24 bytes    | 3 chaves independentes   |
+		|   2   | K1, K2, K3 = K1  | 16 bytes    | 2 chaves (EDE2)          |
+		|   3   | K1 = K2 = K3     |  8 bytes    | igual ao DES simples     |
+		+-------+------------------+-------------+--------------------------+
+
+		O D no meio é o que torna a opção 3 compatível com o DES: E_K(D_K(x)) = x.
+		Por causa do meet-in-the-middle, a opção 1 tem ~112 bits de segurança
+		efetiva, não 168.
+
+	DESX (Rivest, 1984): whitening antes e depois do DES.
+
+		C = K2 XOR E_K(P XOR K1)
+
+		A chave tem 24 bytes: K || K1 || K2. Custa só dois XORs e torna a
+		busca exaustiva bem mais cara (cerca de 2^(119-m) cifragens com 2^m
+		pares conhecidos, segundo Kilian e Rogaway), mas não ajuda contra
+		criptoanálise diferencial ou linear, que atacam o DES interno.
+
+	Os dois tipos implementam cipher.Block, então funcionam com os modos de
+	operação de ../modes e com crypto/cipher.
+*/
+
+package main
+
+import (
+	"crypto/cipher"
+	"fmt"
+)
+
+// KeySizeError indica uma chave com tamanho inválido para a cifra.
+type KeySizeError int
+
+func (k KeySizeError) Error() string {
+	return fmt.Sprintf("des: tamanho de chave inválido: %d bytes", int(k))
+}
+
+// TripleDES guarda as três instâncias do DES usadas em EDE.
+type TripleDES struct {
+	option     int
+	k1, k2, k3 *DES
+}
+
+var _ cipher.Block = (*TripleDES)(nil)
+var _ cipher.Block = (*DESX)(nil)
+
+/*
+NewTripleDES: Cria o 3DES; a opção de chaveamento sai do tamanho da chave.
+
+  - 24 bytes: opção 1 (K1 || K2 || K3)
+  - 16 bytes: opção 2 (K1 || K2, com K3 = K1)
+  - 8 bytes: opção 3 (K1 = K2 = K3, equivale ao DES)
+*/
+func NewTripleDES(key []byte) (*TripleDES, error) {
+	var k1, k2, k3 [KeySize]byte
+	t := &TripleDES{}
+
+	switch len(key) {
+	case 24:
+		t.option = 1
+		copy(k1[:], key[0:8])
+		copy(k2[:], key[8:16])
+		copy(k3[:], key[16:24])
+	case 16:
+		t.option = 2
+		copy(k1[:], key[0:8])
+		copy(k2[:], key[8:16])
+		k3 = k1
+	case 8:
+		t.option = 3
+		copy(k1[:], key)
+		k2, k3 = k1, k1
+	default:
+		return nil, KeySizeError(len(key))
+	}
+
+	var err error
+	if t.k1, err = New(k1); err != nil {
+		return nil, err
+	}
+	if t.k2, err = New(k2); err != nil {
+		return nil, err
+	}
+	if t.k3, err = New(k3); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// KeyingOption devolve a opção de chaveamento do SP 800-67 (1, 2 ou 3).
+func (t *TripleDES) KeyingOption() int { return t.option }
+
+func (t *TripleDES) BlockSize() int { return BlockSize }
+
+func (t *TripleDES) Encrypt(dst, src []byte) {
+	x := loadBlock(dst, src)
+	x, _ = t.k1.Encrypt(x)
+	x, _ = t.k2.Decrypt(x)
+	x, _ = t.k3.Encrypt(x)
+	copy(dst, x[:])
+}
+
+func (t *TripleDES) Decrypt(dst, src []byte) {
+	x := loadBlock(dst, src)
+	x, _ = t.k3.Decrypt(x)
+	x, _ = t.k2.Encrypt(x)
+	x, _ = t.k1.Decrypt(x)
+	copy(dst, x[:])
+}
+
+// DESX é o DES com whitening de entrada (pre) e saída (post).
+type DESX struct {
+	des  *DES
+	pre  [BlockSize]byte
+	post [BlockSize]byte
+}
+
+// NewDESX cria o DESX a partir de 24 bytes: K || K1 (pre) || K2 (post).
+func NewDESX(key []byte) (*DESX, error) {
+	if len(key) != 24 {
+		return nil, KeySizeError(len(key))
+	}
+
+	var k [KeySize]byte
+	copy(k[:], key[:8])
+	d, err := New(k)
+	if err != nil {
+		return nil, err
+	}
+
+	x := &DESX{des: d}
+	copy(x.pre[:], key[8:16])
+	copy(x.post[:], key[16:24])
+	return x, nil
+}
+
+func (x *DESX) BlockSize() int { return BlockSize }
+
+func (x *DESX) Encrypt(dst, src []byte) {
+	b := loadBlock(dst, src)
+	for i := range b {
+		b[i] ^= x.pre[i]
+	}
+	b, _ = x.des.Encrypt(b)
+	for i := range b {
+		b[i] ^= x.post[i]
+	}
+	copy(dst, b[:])
+}
+
+func (x *DESX) Decrypt(dst, src []byte) {
+	b := loadBlock(dst, src)
+	for i := range b {
+		b[i] ^= x.post[i]
+	}
+	b, _ = x.des.Decrypt(b)
+	for i := range b {
+		b[i] ^= x.pre[i]
+	}
+	copy(dst, b[:])
+}
+
+// loadBlock confere os tamanhos, como crypto/des, e copia o primeiro bloco de src.
+func loadBlock(dst, src []byte) [BlockSize]byte {
+	if len(src) < BlockSize {
+		panic("des: bloco de entrada incompleto")
+	}
+	if len(dst) < BlockSize {
+		panic("des: bloco de saída incompleto")
+	}
+	var b [BlockSize]byte
+	copy(b[:], src)
+	return b
+}
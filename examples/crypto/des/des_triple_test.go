@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"crypto/des"
+	"encoding/hex"
+	"math/rand"
+	"testing"
+)
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestTripleDESSP80067(t *testing.T) {
+	// NIST SP 800-67 Rev. 1, exemplo do apêndice B ("The qufck brown fox jump",
+	// com o erro de digitação original)
+	key := mustHex(t, "0123456789abcdef23456789abcdef01456789abcdef0123")
+	plaintext := mustHex(t, "54686520717566636b2062726f776e20666f78206a756d70")
+	want := mustHex(t, "a826fd8ce53b855fcce21c8112256fe668d5c05dd9b6b900")
+
+	c, err := NewTripleDES(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.KeyingOption() != 1 {
+		t.Errorf("opção %d, esperado 1", c.KeyingOption())
+	}
+
+	got := make([]byte, len(plaintext))
+	for i := 0; i < len(plaintext); i += BlockSize {
+		c.Encrypt(got[i:], plaintext[i:])
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("cifrado %x, esperado %x", got, want)
+	}
+	for i := 0; i < len(got); i += BlockSize {
+		c.Decrypt(got[i:], got[i:])
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Fatalf("decifrado %x", got)
+	}
+}
+
+func TestTripleDESCryptoDES(t *testing.T) {
+	rng := rand.New(rand.NewSource(17))
+
+	for _, size := range []int{24, 16, 8} {
+		key := make([]byte, size)
+		rng.Read(key)
+
+		// crypto/des só aceita 24 bytes: K1 || K2 || K3 explícitos
+		var full []byte
+		switch size {
+		case 24:
+			full = key
+		case 16:
+			full = append(append([]byte{}, key...), key[:8]...)
+		case 8:
+			full = bytes.Repeat(key, 3)
+		}
+		ref, err := des.NewTripleDESCipher(full)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c, err := NewTripleDES(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i := 0; i < 16; i++ {
+			src := make([]byte, BlockSize)
+			rng.Read(src)
+			got, want := make([]byte, BlockSize), make([]byte, BlockSize)
+			c.Encrypt(got, src)
+			ref.Encrypt(want, src)
+			if !bytes.Equal(got, want) {
+				t.Fatalf("chave de %d bytes: cifrado %x, crypto/des %x", size, got, want)
+			}
+			c.Decrypt(got, got)
+			if !bytes.Equal(got, src) {
+				t.Fatalf("chave de %d bytes: decifrado %x", size, got)
+			}
+		}
+	}
+}
+
+func TestTripleDESOpcao3IgualDES(t *testing.T) {
+	key := mustHex(t, "133457799bbcdff1")
+	c, _ := NewTripleDES(key)
+	if c.KeyingOption() != 3 {
+		t.Errorf("opção %d, esperado 3", c.KeyingOption())
+	}
+	got := make([]byte, BlockSize)
+	c.Encrypt(got, mustHex(t, "0123456789abcdef"))
+	if want := mustHex(t, "85e813540f0ab405"); !bytes.Equal(got, want) {
+		t.Errorf("cifrado %x, esperado %x", got, want)
+	}
+}
+
+func TestDESX(t *testing.T) {
+	rng := rand.New(rand.NewSource(18))
+	key := make([]byte, 24)
+	rng.Read(key)
+
+	x, err := NewDESX(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref, _ := des.NewCipher(key[:8])
+
+	for i := 0; i < 16; i++ {
+		src := make([]byte, BlockSize)
+		rng.Read(src)
+
+		// C = K2 XOR DES_K(P XOR K1), calculado com crypto/des
+		want := make([]byte, BlockSize)
+		for j := range want {
+			want[j] = src[j] ^ key[8+j]
+		}
+		ref.Encrypt(want, want)
+		for j := range want {
+			want[j] ^= key[16+j]
+		}
+
+		got := make([]byte, BlockSize)
+		x.Encrypt(got, src)
+		if !bytes.Equal(got, want) {
+			t.Fatalf("cifrado %x, esperado %x", got, want)
+		}
+		x.Decrypt(got, got)
+		if !bytes.Equal(got, src) {
+			t.Fatalf("decifrado %x", got)
+		}
+	}
+}
+
+func TestTamanhoDeChaveInvalido(t *testing.T) {
+	if _, err := NewTripleDES(make([]byte, 12)); err != KeySizeError(12) {
+		t.Errorf("3DES com 12 bytes: err = %v", err)
+	}
+	if _, err := NewDESX(make([]byte, 16)); err != KeySizeError(16) {
+		t.Errorf("DESX com 16 bytes: err = %v", err)
+	}
+}
@@ -0,0 +1,142 @@
+/*
+	S-DES: Simplified DES (E. Schaefer, 1996)
+
+	Versão didática do DES com bloco de 8 bits e chave de 10 bits. Mantém a
+	estrutura de Feistel, as permutações por tabela e as S-boxes, mas em
+	tamanho que dá para acompanhar no papel.
+
+	Estruturas usadas (todas 1-based, mesmo formato das tabelas do DES):
+
+		- SDESP10  (10 -> 10):  Permutação da chave
+		- SDESP8   (10 -> 8):   Seleção das subchaves K1 e K2
+		- SDESIP   (8 -> 8):    Permutação inicial
+		- SDESIPInv(8 -> 8):    Permutação final
+		- SDESEP   (4 -> 8):    Expansão/permutação da metade direita
+		- SDESP4   (4 -> 4):    Permutação após as S-boxes
+		- S0, S1   (4 -> 2):    S-boxes, linha = bits 1 e 4, coluna = bits 2 e 3
+
+	Cifragem:
+
+		IP -> fK1 -> SW -> fK2 -> IP^-1
+
+		fK(L, R) = (L XOR F(R, K), R)
+		F(R, K)  = P4(S0 || S1 de EP(R) XOR K)
+
+	Geração de subchaves:
+
+		P10 -> LS-1 nas duas metades de 5 bits -> P8 = K1
+		    -> LS-2 nas duas metades           -> P8 = K2
+
+	Os blocos e metades ficam alinhados à esquerda em slices de bytes (MSB
+	primeiro), para reaproveitar permute, getBit e setBit de des.go.
+*/
+
+package main
+
+import "fmt"
+
+var SDESP10 = [10]uint8{3, 5, 2, 7, 4, 10, 1, 9, 8, 6}
+var SDESP8 = [8]uint8{6, 3, 7, 4, 8, 5, 10, 9}
+var SDESIP = [8]uint8{2, 6, 3, 1, 4, 8, 5, 7}
+var SDESIPInv = [8]uint8{4, 1, 3, 5, 7, 2, 8, 6}
+var SDESEP = [8]uint8{4, 1, 2, 3, 2, 3, 4, 1}
+var SDESP4 = [4]uint8{2, 4, 3, 1}
+
+var S0 = [4][4]uint8{
+	{1, 0, 3, 2},
+	{3, 2, 1, 0},
+	{0, 2, 1, 3},
+	{3, 1, 3, 2},
+}
+
+var S1 = [4][4]uint8{
+	{0, 1, 2, 3},
+	{2, 0, 1, 3},
+	{3, 0, 1, 0},
+	{2, 1, 0, 3},
+}
+
+// SDES guarda as duas subchaves de 8 bits.
+type SDES struct {
+	k1, k2 byte
+}
+
+/*
+NewSDES: Cria o S-DES a partir de uma chave de 10 bits (0 a 1023).
+
+Retorna erro se a chave tiver mais de 10 bits.
+*/
+func NewSDES(key uint16) (*SDES, error) {
+	if key >= 1<<10 {
+		return nil, fmt.Errorf("s-des: chave %#x tem mais de 10 bits", key)
+	}
+	k1, k2 := sdesSubkeys(key)
+	return &SDES{k1: k1, k2: k2}, nil
+}
+
+// sdesSubkeys segue generateSubkeys de des.go com metades de 5 bits.
+func sdesSubkeys(key uint16) (byte, byte) {
+	keyBytes := []byte{byte(key >> 2), byte(key << 6)} // 10 bits alinhados à esquerda
+	bits := permuteBits(bytesToBits(keyBytes)[:10], SDESP10[:])
+
+	C := make([]byte, 5)
+	D := make([]byte, 5)
+	copy(C, bits[:5])
+	copy(D, bits[5:])
+
+	var subkeys [2]byte
+	for i, shift := range []int{1, 2} {
+		C = rotateLeft(C, shift)
+		D = rotateLeft(D, shift)
+
+		CD := append(append([]byte{}, C...), D...)
+		subkeys[i] = bitsToBytes(permuteBits(CD, SDESP8[:]))[0]
+	}
+	return subkeys[0], subkeys[1]
+}
+
+// sdesSbox consulta S0 ou S1 com 4 bits b1b2b3b4: linha = b1b4, coluna = b2b3.
+func sdesSbox(s *[4][4]uint8, in byte) byte {
+	row := (in>>2)&0x2 | in&0x1
+	col := (in >> 1) & 0x3
+	return s[row][col]
+}
+
+/*
+sdesF: Função F do S-DES.
+
+	R (4 bits, no nibble alto) -> EP (8) -> XOR subchave -> S0 || S1 (4) -> P4
+*/
+func sdesF(R, subkey byte) byte {
+	x := permute([]byte{R}, SDESEP[:])[0] ^ subkey
+
+	out := sdesSbox(&S0, x>>4)<<6 | sdesSbox(&S1, x&0xF)<<4
+	return permute([]byte{out}, SDESP4[:])[0]
+}
+
+// fK aplica L = L XOR F(R, K) sobre um bloco de 8 bits (L no nibble alto).
+func fK(block, subkey byte) byte {
+	L, R := block&0xF0, block<<4
+	return (L ^ sdesF(R, subkey)) | R>>4
+}
+
+// sw troca as metades de 4 bits.
+func sw(block byte) byte {
+	return block<<4 | block>>4
+}
+
+func (s *SDES) Encrypt(plaintext byte) byte {
+	x := permute([]byte{plaintext}, SDESIP[:])[0]
+	x = fK(x, s.k1)
+	x = sw(x)
+	x = fK(x, s.k2)
+	return permute([]byte{x}, SDESIPInv[:])[0]
+}
+
+func (s *SDES) Decrypt(ciphertext byte) byte {
+	x := permute([]byte{ciphertext}, SDESIP[:])[0]
+	x = fK(x, s.k2)
+	x = sw(x)
+	x = fK(x, s.k1)
+	return permute([]byte{x}, SDESIPInv[:])[0]
+}
@@ -0,0 +1,222 @@
+/*
+	Ataques ao S-DES
+
+	Força bruta: com 10 bits de chave basta testar as 1024 chaves. Um par
+	(P, C) de 8 bits deixa em média 1024/256 = 4 chaves; cada par adicional
+	divide esse número por 256.
+
+	Criptoanálise diferencial da segunda rodada (exemplo trabalhado):
+
+		Depois de IP, o plaintext é (L0, R0) e o ciphertext é (L2, R2) com
+
+			R2 = R1 = L0 XOR F(R0, K1)
+			L2 = R0 XOR F(R1, K2)
+
+		Logo o atacante conhece, para cada par, a entrada R1 = R2 e a saída
+		F(R1, K2) = L2 XOR R0 da última rodada. Para dois pares:
+
+			ΔX = EP(R1) XOR EP(R1')         (não depende de K2)
+			ΔY = P4^-1(F XOR F')             (diferença na saída de S0 || S1)
+
+		Em cada S-box, só sobrevivem os 4 bits k de K2 com
+		S(x XOR k) XOR S(x' XOR k) = ΔY. A DDT diz quantos são: a entrada
+		DDT[Δin][Δout] é exatamente o número de candidatos.
+
+		Com K2 conhecido, P8 revela 8 dos 10 bits da chave; os 2 restantes
+		saem testando 4 chaves.
+*/
+
+package main
+
+import "fmt"
+
+// SDESPair é um par conhecido de plaintext e ciphertext.
+type SDESPair struct {
+	Plaintext  byte
+	Ciphertext byte
+}
+
+// SDESBruteForce testa as 1024 chaves e devolve as compatíveis com todos os pares.
+func SDESBruteForce(pairs []SDESPair) []uint16 {
+	var keys []uint16
+	for k := uint16(0); k < 1<<10; k++ {
+		s, _ := NewSDES(k)
+		ok := true
+		for _, p := range pairs {
+			if s.Encrypt(p.Plaintext) != p.Ciphertext {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+/*
+SDESDDT: Tabela de distribuição de diferenças de uma S-box 4 -> 2.
+
+	DDT[a][b] = #{x : S(x) XOR S(x XOR a) = b}
+*/
+func SDESDDT(s *[4][4]uint8) [16][4]int {
+	var ddt [16][4]int
+	for a := byte(0); a < 16; a++ {
+		for x := byte(0); x < 16; x++ {
+			ddt[a][sdesSbox(s, x)^sdesSbox(s, x^a)]++
+		}
+	}
+	return ddt
+}
+
+// sdesP4Inv desfaz P4: sdesP4Inv[SDESP4[i]-1] = i+1
+var sdesP4Inv = [4]uint8{4, 1, 3, 2}
+
+// lastRound extrai de um par a entrada R1 e a saída F(R1, K2) da segunda rodada.
+func lastRound(p SDESPair) (in, out byte) {
+	x := permute([]byte{p.Plaintext}, SDESIP[:])[0]
+	y := permute([]byte{p.Ciphertext}, SDESIP[:])[0]
+	R0 := x << 4
+	in = y << 4
+	out = y&0xF0 ^ R0
+	return in, out
+}
+
+/*
+SDESK2Candidates: Filtra os 256 valores de K2 com a diferencial da segunda
+rodada. Devolve os candidatos que sobrevivem a todas as combinações de
+pares e, para cada par de pares usado, quantos candidatos restavam.
+*/
+func SDESK2Candidates(pairs []SDESPair) ([]byte, []int) {
+	alive := make([]bool, 256)
+	for k := range alive {
+		alive[k] = true
+	}
+
+	var history []int
+	for i := 0; i < len(pairs); i++ {
+		for j := i + 1; j < len(pairs); j++ {
+			in1, out1 := lastRound(pairs[i])
+			in2, out2 := lastRound(pairs[j])
+			if in1 == in2 {
+				continue
+			}
+			x1 := permute([]byte{in1}, SDESEP[:])[0]
+			x2 := permute([]byte{in2}, SDESEP[:])[0]
+			dy := permute([]byte{out1 ^ out2}, sdesP4Inv[:])[0]
+
+			n := 0
+			for k := 0; k < 256; k++ {
+				if !alive[k] {
+					continue
+				}
+				a, b := x1^byte(k), x2^byte(k)
+				d0 := sdesSbox(&S0, a>>4) ^ sdesSbox(&S0, b>>4)
+				d1 := sdesSbox(&S1, a&0xF) ^ sdesSbox(&S1, b&0xF)
+				if d0<<6|d1<<4 != dy {
+					alive[k] = false
+					continue
+				}
+				n++
+			}
+			history = append(history, n)
+		}
+	}
+
+	var candidates []byte
+	for k, ok := range alive {
+		if ok {
+			candidates = append(candidates, byte(k))
+		}
+	}
+	return candidates, history
+}
+
+/*
+SDESDifferentialAttack: Recupera a chave a partir de pares conhecidos.
+
+ 1. Filtra K2 pela diferencial da segunda rodada.
+ 2. Para cada K2 restante, percorre só as chaves cujo K2 bate e confere
+    com os pares.
+
+Devolve as chaves encontradas e quantas cifragens completas foram feitas
+(comparar com as 1024 da força bruta).
+*/
+func SDESDifferentialAttack(pairs []SDESPair) ([]uint16, int) {
+	k2s, _ := SDESK2Candidates(pairs)
+	want := make(map[byte]bool)
+	for _, k2 := range k2s {
+		want[k2] = true
+	}
+
+	var keys []uint16
+	trials := 0
+	for k := uint16(0); k < 1<<10; k++ {
+		_, k2 := sdesSubkeys(k)
+		if !want[k2] {
+			continue
+		}
+		trials++
+		s, _ := NewSDES(k)
+		ok := true
+		for _, p := range pairs {
+			if s.Encrypt(p.Plaintext) != p.Ciphertext {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			keys = append(keys, k)
+		}
+	}
+	return keys, trials
+}
+
+func printSDESDDT(name string, s *[4][4]uint8) {
+	ddt := SDESDDT(s)
+	fmt.Printf("DDT de %s (linhas: Δentrada, colunas: Δsaída)\n", name)
+	fmt.Println("       00 01 10 11")
+	for a, row := range ddt {
+		fmt.Printf("  %04b", a)
+		for _, n := range row {
+			fmt.Printf(" %2d", n)
+		}
+		fmt.Println()
+	}
+}
+
+// runSDES é a ferramenta de demonstração: go run . sdes
+func runSDES() {
+	var key uint16 = 0b1010000010
+	var plaintext byte = 0b10010111
+
+	s, err := NewSDES(key)
+	if err != nil {
+		panic(err)
+	}
+	ciphertext := s.Encrypt(plaintext)
+	fmt.Printf("S-DES chave %010b: K1 = %08b, K2 = %08b\n", key, s.k1, s.k2)
+	fmt.Printf("Plaintext %08b -> ciphertext %08b -> %08b\n\n", plaintext, ciphertext, s.Decrypt(ciphertext))
+
+	// Força bruta nas 1024 chaves com um e com dois pares
+	pairs := []SDESPair{{plaintext, ciphertext}}
+	fmt.Printf("Força bruta com 1 par:  %d chaves\n", len(SDESBruteForce(pairs)))
+	pairs = append(pairs, SDESPair{0x3C, s.Encrypt(0x3C)})
+	fmt.Printf("Força bruta com 2 pares: %010b\n\n", SDESBruteForce(pairs))
+
+	printSDESDDT("S0", &S0)
+	fmt.Println()
+	printSDESDDT("S1", &S1)
+	fmt.Println()
+
+	// Ataque diferencial com alguns pares conhecidos
+	for _, p := range []byte{0x00, 0x5A, 0xF1, 0x82} {
+		pairs = append(pairs, SDESPair{p, s.Encrypt(p)})
+	}
+	k2s, history := SDESK2Candidates(pairs)
+	fmt.Printf("Candidatos a K2 após cada par de pares: %v\n", history)
+	fmt.Printf("K2 restantes: %08b\n", k2s)
+	keys, trials := SDESDifferentialAttack(pairs)
+	fmt.Printf("Chave recuperada: %010b (%d cifragens em vez de %d)\n", keys, trials, 1<<10)
+}
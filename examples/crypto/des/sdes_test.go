@@ -0,0 +1,88 @@
+package main
+
+import "testing"
+
+func TestSDESStallings(t *testing.T) {
+	// W. Stallings, Cryptography and Network Security, apêndice do S-DES
+	s, err := NewSDES(0b1010000010)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.k1 != 0b10100100 || s.k2 != 0b01000011 {
+		t.Fatalf("K1 = %08b, K2 = %08b", s.k1, s.k2)
+	}
+	if got := s.Encrypt(0b10010111); got != 0b00111000 {
+		t.Errorf("cifrado %08b, esperado 00111000", got)
+	}
+	if got := s.Decrypt(0b00111000); got != 0b10010111 {
+		t.Errorf("decifrado %08b, esperado 10010111", got)
+	}
+}
+
+func TestSDESIdaEVolta(t *testing.T) {
+	for k := uint16(0); k < 1<<10; k += 37 {
+		s, _ := NewSDES(k)
+		for p := 0; p < 256; p++ {
+			if got := s.Decrypt(s.Encrypt(byte(p))); got != byte(p) {
+				t.Fatalf("chave %010b: D(E(%08b)) = %08b", k, p, got)
+			}
+		}
+	}
+}
+
+func TestSDESChaveInvalida(t *testing.T) {
+	if _, err := NewSDES(1 << 10); err == nil {
+		t.Error("esperado erro para chave de 11 bits")
+	}
+}
+
+func TestSDESDDT(t *testing.T) {
+	for _, s := range []*[4][4]uint8{&S0, &S1} {
+		ddt := SDESDDT(s)
+		if ddt[0][0] != 16 {
+			t.Errorf("DDT[0][0] = %d", ddt[0][0])
+		}
+		for a, row := range ddt {
+			sum := 0
+			for _, n := range row {
+				if n%2 != 0 {
+					t.Errorf("DDT[%04b] tem entrada ímpar %d", a, n)
+				}
+				sum += n
+			}
+			if sum != 16 {
+				t.Errorf("linha %04b soma %d", a, sum)
+			}
+		}
+	}
+}
+
+func TestSDESBruteForceEDiferencial(t *testing.T) {
+	key := uint16(0b0111010011)
+	s, _ := NewSDES(key)
+
+	var pairs []SDESPair
+	for _, p := range []byte{0x00, 0x3C, 0x5A, 0x97, 0xF1, 0x82} {
+		pairs = append(pairs, SDESPair{p, s.Encrypt(p)})
+	}
+
+	found := false
+	for _, k := range SDESBruteForce(pairs) {
+		found = found || k == key
+	}
+	if !found {
+		t.Fatal("força bruta não encontrou a chave")
+	}
+
+	keys, trials := SDESDifferentialAttack(pairs)
+	found = false
+	for _, k := range keys {
+		found = found || k == key
+	}
+	if !found {
+		t.Fatalf("ataque diferencial não encontrou a chave: %010b", keys)
+	}
+	if trials >= 1<<10 {
+		t.Errorf("ataque diferencial fez %d cifragens, não melhor que força bruta", trials)
+	}
+}
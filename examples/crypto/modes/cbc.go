@@ -0,0 +1,60 @@
+package modes
+
+import "crypto/cipher"
+
+/*
+EncryptCBC: Cifra no modo Cipher Block Chaining.
+
+	     P1              P2              P3
+	     |               |               |
+	IV ->XOR     +----->XOR     +----->XOR
+	     |       |       |       |       |
+	     E_k     |       E_k     |       E_k
+	     |       |       |       |       |
+	     C1 -----+       C2 -----+       C3
+
+	Cada bloco é misturado com o cifrado anterior antes de passar pela cifra,
+	então blocos iguais geram cifrados diferentes. O IV deve ser imprevisível.
+
+	A entrada deve ser múltipla do bloco; use Pad antes se necessário.
+*/
+func EncryptCBC(b cipher.Block, iv, plaintext []byte) ([]byte, error) {
+	if err := checkIV(b, iv); err != nil {
+		return nil, err
+	}
+	bs := b.BlockSize()
+	if len(plaintext)%bs != 0 {
+		return nil, ErrNotFullBlocks
+	}
+
+	out := make([]byte, len(plaintext))
+	prev := iv
+	for i := 0; i < len(plaintext); i += bs {
+		block := out[i : i+bs]
+		xorBytes(block, plaintext[i:i+bs], prev)
+		b.Encrypt(block, block)
+		prev = block
+	}
+	return out, nil
+}
+
+// DecryptCBC desfaz EncryptCBC: Pi = D_k(Ci) XOR Ci-1, com C0 = IV.
+func DecryptCBC(b cipher.Block, iv, ciphertext []byte) ([]byte, error) {
+	if err := checkIV(b, iv); err != nil {
+		return nil, err
+	}
+	bs := b.BlockSize()
+	if len(ciphertext)%bs != 0 {
+		return nil, ErrNotFullBlocks
+	}
+
+	out := make([]byte, len(ciphertext))
+	prev := iv
+	for i := 0; i < len(ciphertext); i += bs {
+		block := out[i : i+bs]
+		b.Decrypt(block, ciphertext[i:i+bs])
+		xorBytes(block, block, prev)
+		prev = ciphertext[i : i+bs]
+	}
+	return out, nil
+}
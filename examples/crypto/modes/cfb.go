@@ -0,0 +1,52 @@
+package modes
+
+import "crypto/cipher"
+
+/*
+EncryptCFB: Cifra no modo Cipher Feedback com segmento do tamanho do bloco
+(CFB128 para AES, CFB64 para DES).
+
+	IV ----> E_k    +--> E_k    +--> E_k
+	         |      |    |      |    |
+	    P1->XOR     |P2->XOR    |P3->XOR
+	         |      |    |      |    |
+	         C1 ----+    C2 ----+    C3
+
+	O cifrado anterior realimenta a cifra, que passa a funcionar como gerador
+	de fluxo. Só a função de cifragem (b.Encrypt) é usada, inclusive para
+	decifrar. O último bloco pode ser parcial, sem necessidade de padding.
+*/
+func EncryptCFB(b cipher.Block, iv, plaintext []byte) ([]byte, error) {
+	return cfb(b, iv, plaintext, false)
+}
+
+// DecryptCFB desfaz EncryptCFB: Pi = Ci XOR E_k(Ci-1), com C0 = IV.
+func DecryptCFB(b cipher.Block, iv, ciphertext []byte) ([]byte, error) {
+	return cfb(b, iv, ciphertext, true)
+}
+
+func cfb(b cipher.Block, iv, in []byte, decrypt bool) ([]byte, error) {
+	if err := checkIV(b, iv); err != nil {
+		return nil, err
+	}
+	bs := b.BlockSize()
+
+	out := make([]byte, len(in))
+	feedback := make([]byte, bs)
+	keystream := make([]byte, bs)
+	copy(feedback, iv)
+
+	for i := 0; i < len(in); i += bs {
+		end := min(i+bs, len(in))
+		b.Encrypt(keystream, feedback)
+		xorBytes(out[i:end], in[i:end], keystream)
+
+		// O próximo feedback é sempre o cifrado
+		if decrypt {
+			copy(feedback, in[i:end])
+		} else {
+			copy(feedback, out[i:end])
+		}
+	}
+	return out, nil
+}
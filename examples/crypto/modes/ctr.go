@@ -0,0 +1,46 @@
+package modes
+
+import "crypto/cipher"
+
+/*
+CTR: Cifra ou decifra no modo Counter (a operação é a mesma).
+
+	CTR   -> E_k     CTR+1 -> E_k     CTR+2 -> E_k
+	          |                |                |
+	     P1->XOR          P2->XOR          P3->XOR
+	          |                |                |
+	          C1               C2               C3
+
+	O contador inicial ocupa um bloco inteiro e é incrementado como um inteiro
+	big-endian (SP 800-38A, apêndice B.1). Como os blocos são independentes,
+	o CTR pode ser paralelizado e permite acesso aleatório à mensagem.
+*/
+func CTR(b cipher.Block, counter, in []byte) ([]byte, error) {
+	if err := checkIV(b, counter); err != nil {
+		return nil, err
+	}
+	bs := b.BlockSize()
+
+	out := make([]byte, len(in))
+	ctr := make([]byte, bs)
+	keystream := make([]byte, bs)
+	copy(ctr, counter)
+
+	for i := 0; i < len(in); i += bs {
+		end := min(i+bs, len(in))
+		b.Encrypt(keystream, ctr)
+		xorBytes(out[i:end], in[i:end], keystream)
+		incCounter(ctr)
+	}
+	return out, nil
+}
+
+// incCounter soma 1 ao contador big-endian, propagando o "vai um".
+func incCounter(ctr []byte) {
+	for i := len(ctr) - 1; i >= 0; i-- {
+		ctr[i]++
+		if ctr[i] != 0 {
+			return
+		}
+	}
+}
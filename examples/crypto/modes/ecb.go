@@ -0,0 +1,42 @@
+package modes
+
+import "crypto/cipher"
+
+/*
+EncryptECB: Cifra cada bloco de forma independente (Electronic Codebook).
+
+	P1        P2        P3
+	|         |         |
+	E_k       E_k       E_k
+	|         |         |
+	C1        C2        C3
+
+	Blocos iguais geram cifrados iguais, o que vaza a estrutura da mensagem
+	(o famoso "pinguim do ECB"). Está aqui apenas para fins didáticos.
+
+	A entrada deve ser múltipla do bloco; use Pad antes se necessário.
+*/
+func EncryptECB(b cipher.Block, plaintext []byte) ([]byte, error) {
+	return ecb(b, plaintext, b.Encrypt)
+}
+
+// DecryptECB desfaz EncryptECB; o padding (se houver) deve ser removido com Unpad.
+func DecryptECB(b cipher.Block, ciphertext []byte) ([]byte, error) {
+	return ecb(b, ciphertext, b.Decrypt)
+}
+
+func ecb(b cipher.Block, in []byte, fn func(dst, src []byte)) ([]byte, error) {
+	if err := checkBlock(b); err != nil {
+		return nil, err
+	}
+	bs := b.BlockSize()
+	if len(in)%bs != 0 {
+		return nil, ErrNotFullBlocks
+	}
+
+	out := make([]byte, len(in))
+	for i := 0; i < len(in); i += bs {
+		fn(out[i:i+bs], in[i:i+bs])
+	}
+	return out, nil
+}
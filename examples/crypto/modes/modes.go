@@ -0,0 +1,125 @@
+/*
+
+Modos de Operação para Cifras de Bloco
+
+Uma cifra de bloco (AES, DES, RC2...) só sabe cifrar um bloco de tamanho fixo.
+Os modos de operação definem como encadear várias chamadas da cifra para
+processar mensagens de qualquer tamanho (NIST SP 800-38A):
+
+	+------+-----------+--------------------------+------------------------------+
+	| Modo | Padding?  | Usa IV/contador?         | Comportamento                |
+	+------+-----------+--------------------------+------------------------------+
+	| ECB  | sim       | não                      | cada bloco cifrado isolado   |
+	| CBC  | sim       | IV aleatório             | Ci = E(Pi XOR Ci-1)          |
+	| CFB  | não       | IV aleatório             | Ci = Pi XOR E(Ci-1)          |
+	| OFB  | não       | IV único (nonce)         | Oi = E(Oi-1), Ci = Pi XOR Oi |
+	| CTR  | não       | contador único (nonce)   | Ci = Pi XOR E(CTR + i)       |
+	+------+-----------+--------------------------+------------------------------+
+
+ECB e CBC trabalham com blocos completos, por isso exigem padding (PKCS#7).
+CFB, OFB e CTR transformam a cifra de bloco em uma cifra de fluxo e aceitam
+qualquer tamanho de mensagem.
+
+Todas as funções recebem um cipher.Block, então funcionam com qualquer cifra
+de 8 ou 16 bytes do repositório que implemente essa interface, além de
+crypto/aes e crypto/des.
+
+*/
+
+package modes
+
+import (
+	"crypto/cipher"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrBlockSize      = errors.New("modes: a cifra precisa ter blocos de 8 ou 16 bytes")
+	ErrNotFullBlocks  = errors.New("modes: entrada não é múltipla do tamanho do bloco")
+	ErrInvalidPadding = errors.New("modes: padding PKCS#7 inválido")
+	ErrPadBlockSize   = errors.New("modes: tamanho de bloco do PKCS#7 fora de 1 a 255")
+)
+
+// IVSizeError indica um IV (ou contador inicial) com tamanho diferente do bloco.
+type IVSizeError struct {
+	Got, Want int
+}
+
+func (e IVSizeError) Error() string {
+	return fmt.Sprintf("modes: IV com %d bytes, esperado %d", e.Got, e.Want)
+}
+
+func checkBlock(b cipher.Block) error {
+	switch b.BlockSize() {
+	case 8, 16:
+		return nil
+	default:
+		return ErrBlockSize
+	}
+}
+
+func checkIV(b cipher.Block, iv []byte) error {
+	if err := checkBlock(b); err != nil {
+		return err
+	}
+	if len(iv) != b.BlockSize() {
+		return IVSizeError{Got: len(iv), Want: b.BlockSize()}
+	}
+	return nil
+}
+
+func xorBytes(dst, a, b []byte) {
+	for i := range dst {
+		dst[i] = a[i] ^ b[i]
+	}
+}
+
+/*
+Pad: Aplica padding PKCS#7 (RFC 5652, seção 6.3).
+
+	Sempre adiciona entre 1 e blockSize bytes, todos com o valor n (número de
+	bytes adicionados). Se a mensagem já for múltipla do bloco, um bloco
+	inteiro de padding é acrescentado, para que Unpad nunca seja ambíguo.
+
+	Exemplo com bloco de 8 bytes:
+		"ABCDE"    -> "ABCDE" 03 03 03
+		"ABCDEFGH" -> "ABCDEFGH" 08 08 08 08 08 08 08 08
+
+	O valor n ocupa um byte, então blockSize precisa estar entre 1 e 255;
+	fora disso Pad gera um panic com ErrPadBlockSize (é erro de programação,
+	não de dados).
+*/
+func Pad(data []byte, blockSize int) []byte {
+	if blockSize < 1 || blockSize > 255 {
+		panic(ErrPadBlockSize)
+	}
+	n := blockSize - len(data)%blockSize
+	out := make([]byte, len(data)+n)
+	copy(out, data)
+	for i := len(data); i < len(out); i++ {
+		out[i] = byte(n)
+	}
+	return out
+}
+
+// Unpad remove o padding PKCS#7, validando o tamanho e todos os bytes de padding.
+func Unpad(data []byte, blockSize int) ([]byte, error) {
+	if blockSize < 1 || blockSize > 255 {
+		return nil, ErrPadBlockSize
+	}
+	if len(data) == 0 || len(data)%blockSize != 0 {
+		return nil, ErrNotFullBlocks
+	}
+
+	n := int(data[len(data)-1])
+	if n == 0 || n > blockSize {
+		return nil, ErrInvalidPadding
+	}
+	for _, v := range data[len(data)-n:] {
+		if int(v) != n {
+			return nil, ErrInvalidPadding
+		}
+	}
+	return data[:len(data)-n], nil
+}
@@ -0,0 +1,289 @@
+package modes
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/des"
+	"encoding/hex"
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(strings.ReplaceAll(s, " ", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// Vetores do NIST SP 800-38A, apêndice F (AES-128)
+const (
+	nistKey       = "2b7e151628aed2a6abf7158809cf4f3c"
+	nistIV        = "000102030405060708090a0b0c0d0e0f"
+	nistCounter   = "f0f1f2f3f4f5f6f7f8f9fafbfcfdfeff"
+	nistPlaintext = "6bc1bee22e409f96e93d7e117393172a ae2d8a571e03ac9c9eb76fac45af8e51" +
+		" 30c81c46a35ce411e5fbc1191a0a52ef f69f2445df4f9b17ad2b417be66c3710"
+)
+
+func TestSP800_38A(t *testing.T) {
+	block, err := aes.NewCipher(mustHex(t, nistKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plaintext := mustHex(t, nistPlaintext)
+	iv := mustHex(t, nistIV)
+	counter := mustHex(t, nistCounter)
+
+	casos := []struct {
+		nome       string
+		ciphertext string
+		enc, dec   func(in []byte) ([]byte, error)
+	}{
+		{
+			"ECB-AES128 (F.1.1)",
+			"3ad77bb40d7a3660a89ecaf32466ef97 f5d3d58503b9699de785895a96fdbaaf" +
+				" 43b1cd7f598ece23881b00e3ed030688 7b0c785e27e8ad3f8223207104725dd4",
+			func(in []byte) ([]byte, error) { return EncryptECB(block, in) },
+			func(in []byte) ([]byte, error) { return DecryptECB(block, in) },
+		},
+		{
+			"CBC-AES128 (F.2.1)",
+			"7649abac8119b246cee98e9b12e9197d 5086cb9b507219ee95db113a917678b2" +
+				" 73bed6b8e3c1743b7116e69e22229516 3ff1caa1681fac09120eca307586e1a7",
+			func(in []byte) ([]byte, error) { return EncryptCBC(block, iv, in) },
+			func(in []byte) ([]byte, error) { return DecryptCBC(block, iv, in) },
+		},
+		{
+			"CFB128-AES128 (F.3.13)",
+			"3b3fd92eb72dad20333449f8e83cfb4a c8a64537a0b3a93fcde3cdad9f1ce58b" +
+				" 26751f67a3cbb140b1808cf187a4f4df c04b05357c5d1c0eeac4c66f9ff7f2e6",
+			func(in []byte) ([]byte, error) { return EncryptCFB(block, iv, in) },
+			func(in []byte) ([]byte, error) { return DecryptCFB(block, iv, in) },
+		},
+		{
+			"OFB-AES128 (F.4.1)",
+			"3b3fd92eb72dad20333449f8e83cfb4a 7789508d16918f03f53c52dac54ed825" +
+				" 9740051e9c5fecf64344f7a82260edcc 304c6528f659c77866a510d9c1d6ae5e",
+			func(in []byte) ([]byte, error) { return OFB(block, iv, in) },
+			func(in []byte) ([]byte, error) { return OFB(block, iv, in) },
+		},
+		{
+			"CTR-AES128 (F.5.1)",
+			"874d6191b620e3261bef6864990db6ce 9806f66b7970fdff8617187bb9fffdff" +
+				" 5ae4df3edbd5d35e5b4f09020db03eab 1e031dda2fbe03d1792170a0f3009cee",
+			func(in []byte) ([]byte, error) { return CTR(block, counter, in) },
+			func(in []byte) ([]byte, error) { return CTR(block, counter, in) },
+		},
+	}
+
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			want := mustHex(t, c.ciphertext)
+
+			got, err := c.enc(plaintext)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("cifragem: obtido %x, esperado %x", got, want)
+			}
+
+			dec, err := c.dec(want)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(dec, plaintext) {
+				t.Errorf("decifragem: obtido %x, esperado %x", dec, plaintext)
+			}
+		})
+	}
+}
+
+// Compara com crypto/cipher usando cifras de 8 (DES) e 16 bytes (AES),
+// inclusive mensagens com o último bloco parcial nos modos de fluxo.
+func TestComparaComCryptoCipher(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	newDES := func(key []byte) (cipher.Block, error) { return des.NewCipher(key[:8]) }
+	for _, newBlock := range []func([]byte) (cipher.Block, error){aes.NewCipher, newDES} {
+		key := make([]byte, 16)
+		rng.Read(key)
+		block, err := newBlock(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bs := block.BlockSize()
+		iv := make([]byte, bs)
+		rng.Read(iv)
+
+		for n := 0; n <= 5*bs; n++ {
+			msg := make([]byte, n)
+			rng.Read(msg)
+			want := make([]byte, n)
+
+			got, _ := EncryptCFB(block, iv, msg)
+			cipher.NewCFBEncrypter(block, iv).XORKeyStream(want, msg)
+			if !bytes.Equal(got, want) {
+				t.Fatalf("CFB bs=%d n=%d: obtido %x, esperado %x", bs, n, got, want)
+			}
+
+			got, _ = OFB(block, iv, msg)
+			cipher.NewOFB(block, iv).XORKeyStream(want, msg)
+			if !bytes.Equal(got, want) {
+				t.Fatalf("OFB bs=%d n=%d: obtido %x, esperado %x", bs, n, got, want)
+			}
+
+			got, _ = CTR(block, iv, msg)
+			cipher.NewCTR(block, iv).XORKeyStream(want, msg)
+			if !bytes.Equal(got, want) {
+				t.Fatalf("CTR bs=%d n=%d: obtido %x, esperado %x", bs, n, got, want)
+			}
+
+			if n%bs == 0 {
+				got, _ = EncryptCBC(block, iv, msg)
+				cipher.NewCBCEncrypter(block, iv).CryptBlocks(want, msg)
+				if !bytes.Equal(got, want) {
+					t.Fatalf("CBC bs=%d n=%d: obtido %x, esperado %x", bs, n, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestCTRVaiUm(t *testing.T) {
+	ctr := []byte{0x00, 0x01, 0xff, 0xff}
+	incCounter(ctr)
+	if !bytes.Equal(ctr, []byte{0x00, 0x02, 0x00, 0x00}) {
+		t.Fatalf("incCounter: obtido %x", ctr)
+	}
+
+	ctr = []byte{0xff, 0xff}
+	incCounter(ctr)
+	if !bytes.Equal(ctr, []byte{0x00, 0x00}) {
+		t.Fatalf("incCounter com overflow: obtido %x", ctr)
+	}
+}
+
+func TestPKCS7(t *testing.T) {
+	for _, bs := range []int{8, 16} {
+		for n := 0; n <= 2*bs; n++ {
+			msg := bytes.Repeat([]byte{'A'}, n)
+			padded := Pad(msg, bs)
+			if len(padded)%bs != 0 || len(padded) <= n {
+				t.Fatalf("Pad(%d, %d): tamanho %d", n, bs, len(padded))
+			}
+			unpadded, err := Unpad(padded, bs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(unpadded, msg) {
+				t.Fatalf("Unpad(Pad(%x)) = %x", msg, unpadded)
+			}
+		}
+	}
+
+	if got := Pad([]byte("ABCDE"), 8); !bytes.Equal(got, []byte("ABCDE\x03\x03\x03")) {
+		t.Errorf("Pad(ABCDE, 8) = %x", got)
+	}
+
+	invalidos := [][]byte{
+		nil,
+		[]byte("ABCDEFG"),
+		[]byte("ABCDEFG\x00"),
+		[]byte("ABCDEFG\x09"),
+		[]byte("ABCDE\x02\x03\x03"),
+	}
+	for _, in := range invalidos {
+		if _, err := Unpad(in, 8); err == nil {
+			t.Errorf("Unpad(%x) deveria falhar", in)
+		}
+	}
+}
+
+func TestPKCS7BlockSizeInvalido(t *testing.T) {
+	for _, bs := range []int{-1, 0, 256, 1000} {
+		if _, err := Unpad(make([]byte, 256), bs); !errors.Is(err, ErrPadBlockSize) {
+			t.Errorf("Unpad com bloco %d: %v", bs, err)
+		}
+		func() {
+			defer func() {
+				if r := recover(); r != ErrPadBlockSize {
+					t.Errorf("Pad com bloco %d: panic %v, esperado ErrPadBlockSize", bs, r)
+				}
+			}()
+			Pad([]byte("ABC"), bs)
+		}()
+	}
+
+	// Os extremos válidos: 1 e 255 bytes por bloco
+	for _, bs := range []int{1, 255} {
+		padded := Pad([]byte("ABC"), bs)
+		if got, err := Unpad(padded, bs); err != nil || string(got) != "ABC" {
+			t.Errorf("bloco %d: Unpad(Pad(ABC)) = %q, %v", bs, got, err)
+		}
+	}
+}
+
+// Round trip completo com padding em ECB/CBC sobre DES (bloco de 8 bytes).
+func TestPaddingComDES(t *testing.T) {
+	block, err := des.NewCipher([]byte("8bytekey"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	iv := []byte("iv-8byte")
+	msg := []byte("Modos de operação com uma cifra de 64 bits")
+
+	ct, err := EncryptCBC(block, iv, Pad(msg, block.BlockSize()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pt, err := DecryptCBC(block, iv, ct)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pt, err = Unpad(pt, block.BlockSize())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(pt, msg) {
+		t.Fatalf("CBC+PKCS#7: obtido %q, esperado %q", pt, msg)
+	}
+
+	ct, err = EncryptECB(block, Pad(msg, block.BlockSize()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pt, _ = DecryptECB(block, ct)
+	if pt, _ = Unpad(pt, block.BlockSize()); !bytes.Equal(pt, msg) {
+		t.Fatalf("ECB+PKCS#7: obtido %q, esperado %q", pt, msg)
+	}
+}
+
+type blocoDe4 struct{ cipher.Block }
+
+func (blocoDe4) BlockSize() int { return 4 }
+
+func TestErros(t *testing.T) {
+	block, _ := aes.NewCipher(make([]byte, 16))
+
+	if _, err := EncryptECB(block, make([]byte, 15)); !errors.Is(err, ErrNotFullBlocks) {
+		t.Errorf("ECB com bloco parcial: %v", err)
+	}
+	if _, err := DecryptCBC(block, make([]byte, 16), make([]byte, 17)); !errors.Is(err, ErrNotFullBlocks) {
+		t.Errorf("CBC com bloco parcial: %v", err)
+	}
+
+	var ivErr IVSizeError
+	if _, err := CTR(block, make([]byte, 12), nil); !errors.As(err, &ivErr) || ivErr.Want != 16 {
+		t.Errorf("CTR com contador de 12 bytes: %v", err)
+	}
+
+	if _, err := EncryptECB(blocoDe4{block}, make([]byte, 16)); !errors.Is(err, ErrBlockSize) {
+		t.Errorf("cifra com bloco de 4 bytes: %v", err)
+	}
+}
@@ -0,0 +1,33 @@
+package modes
+
+import "crypto/cipher"
+
+/*
+OFB: Cifra ou decifra no modo Output Feedback (a operação é a mesma).
+
+	IV -> E_k --+--> E_k --+--> E_k
+	            |          |     |
+	       P1->XOR    P2->XOR  P3->XOR
+	            |          |     |
+	            C1         C2    C3
+
+	O fluxo de chave depende apenas da chave e do IV, nunca da mensagem.
+	Reutilizar o mesmo IV com a mesma chave expõe P1 XOR P1' diretamente.
+*/
+func OFB(b cipher.Block, iv, in []byte) ([]byte, error) {
+	if err := checkIV(b, iv); err != nil {
+		return nil, err
+	}
+	bs := b.BlockSize()
+
+	out := make([]byte, len(in))
+	keystream := make([]byte, bs)
+	copy(keystream, iv)
+
+	for i := 0; i < len(in); i += bs {
+		end := min(i+bs, len(in))
+		b.Encrypt(keystream, keystream)
+		xorBytes(out[i:end], in[i:end], keystream)
+	}
+	return out, nil
+}
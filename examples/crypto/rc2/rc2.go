@@ -0,0 +1,273 @@
+/*
+	RC2 (Rivest, 1987; RFC 2268)
+
+	Cifra de bloco de 64 bits com chave de 1 a 128 bytes. O bloco é visto
+	como quatro palavras de 16 bits R[0..3] (little-endian) e passa por:
+
+		5 rodadas MIX -> MASH -> 6 rodadas MIX -> MASH -> 5 rodadas MIX
+
+		MIX (para i = 0..3, s = 1, 2, 3, 5):
+			R[i] += K[j] + (R[i-1] & R[i-2]) + (~R[i-1] & R[i-3])
+			R[i] = R[i] <<< s[i]
+			j++
+
+		MASH (para i = 0..3):
+			R[i] += K[R[i-1] & 63]
+
+	Os índices i-1, i-2, i-3 são módulo 4. Cada MIX consome uma palavra de
+	K, então as 16 rodadas usam as 64 palavras da chave expandida. A
+	decifragem faz as operações inversas na ordem contrária (rotação à
+	direita e subtração), com j descendo de 63 a 0.
+
+	Expansão de chave: a chave L[0..T-1] vira 128 bytes L[0..127]
+
+		para i = T..127:      L[i] = PITABLE[(L[i-1] + L[i-T]) mod 256]
+		L[128-T8] = PITABLE[L[128-T8] & TM]
+		para i = 127-T8..0:   L[i] = PITABLE[L[i+1] XOR L[i+T8]]
+
+		T1 = bits efetivos da chave, T8 = ceil(T1/8), TM = 255 >> (8*T8 - T1)
+
+	O parâmetro T1 ("effective key bits") limita a busca exaustiva a 2^T1
+	chaves, qualquer que seja o tamanho de L: foi o que permitiu exportar o
+	RC2 com 40 bits nos anos 90. O segundo laço reescreve L de trás para a
+	frente a partir dos últimos T8 bytes (com o primeiro deles mascarado
+	por TM), então as 64 palavras dependem só desses T1 bits.
+
+	K e L são o mesmo array, visto em palavras ou em bytes:
+
+		K[i] = L[2*i] + 256*L[2*i+1]
+*/
+
+package main
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/bits"
+)
+
+const BlockSize = 8
+
+/*
+PITABLE: Permutação de 0..255 derivada dos dígitos de π (RFC 2268,
+seção 2). É a mesma tabela do MD2.
+*/
+var piTable = [256]byte{
+	217, 120, 249, 196, 25, 221, 181, 237, 40, 233, 253, 121, 74, 160, 216, 157,
+	198, 126, 55, 131, 43, 118, 83, 142, 98, 76, 100, 136, 68, 139, 251, 162,
+	23, 154, 89, 245, 135, 179, 79, 19, 97, 69, 109, 141, 9, 129, 125, 50,
+	189, 143, 64, 235, 134, 183, 123, 11, 240, 149, 33, 34, 92, 107, 78, 130,
+	84, 214, 101, 147, 206, 96, 178, 28, 115, 86, 192, 20, 167, 140, 241, 220,
+	18, 117, 202, 31, 59, 190, 228, 209, 66, 61, 212, 48, 163, 60, 182, 38,
+	111, 191, 14, 218, 70, 105, 7, 87, 39, 242, 29, 155, 188, 148, 67, 3,
+	248, 17, 199, 246, 144, 239, 62, 231, 6, 195, 213, 47, 200, 102, 30, 215,
+	8, 232, 234, 222, 128, 82, 238, 247, 132, 170, 114, 172, 53, 77, 106, 42,
+	150, 26, 210, 113, 90, 21, 73, 116, 75, 159, 208, 94, 4, 24, 164, 236,
+	194, 224, 65, 110, 15, 81, 203, 204, 36, 145, 175, 80, 161, 244, 112, 57,
+	153, 124, 58, 133, 35, 184, 180, 122, 252, 2, 54, 91, 37, 85, 151, 49,
+	45, 93, 250, 152, 227, 138, 146, 174, 5, 223, 41, 16, 103, 108, 186, 201,
+	211, 0, 230, 207, 225, 158, 168, 44, 99, 22, 1, 63, 88, 226, 137, 169,
+	13, 56, 52, 27, 171, 51, 255, 176, 187, 72, 12, 95, 185, 177, 205, 46,
+	197, 243, 219, 71, 229, 165, 156, 119, 10, 166, 32, 104, 254, 127, 193, 173,
+}
+
+// KeySizeError indica uma chave fora de 1 a 128 bytes.
+type KeySizeError int
+
+func (k KeySizeError) Error() string {
+	return fmt.Sprintf("rc2: tamanho de chave inválido: %d bytes (use 1 a 128)", int(k))
+}
+
+// EffectiveBitsError indica T1 fora de 1 a 1024.
+type EffectiveBitsError int
+
+func (t EffectiveBitsError) Error() string {
+	return fmt.Sprintf("rc2: número de bits efetivos inválido: %d (use 1 a 1024)", int(t))
+}
+
+// RC2 guarda as 64 palavras da chave expandida.
+type RC2 struct {
+	K [64]uint16
+}
+
+var _ cipher.Block = (*RC2)(nil)
+
+/*
+New: Cria o RC2 com a chave dada (1 a 128 bytes) e t1 bits efetivos
+(1 a 1024). O uso mais comum é t1 = 8*len(key).
+*/
+func New(key []byte, t1 int) (*RC2, error) {
+	if len(key) < 1 || len(key) > 128 {
+		return nil, KeySizeError(len(key))
+	}
+	if t1 < 1 || t1 > 1024 {
+		return nil, EffectiveBitsError(t1)
+	}
+	return &RC2{K: expandKey(key, t1)}, nil
+}
+
+// expandKey: Expansão de chave da RFC 2268, seção 2.
+func expandKey(key []byte, t1 int) [64]uint16 {
+	var L [128]byte
+	copy(L[:], key)
+
+	// Completa os 128 bytes a partir da chave: cada byte novo depende do
+	// anterior e do que está T posições atrás
+	t := len(key)
+	for i := t; i < 128; i++ {
+		L[i] = piTable[L[i-1]+L[i-t]] // soma de bytes já é mod 256
+	}
+
+	// Reduz a chave a t1 bits efetivos. t8 arredonda para cima e tm
+	// descarta os bits que sobram no primeiro byte usado (t1 = 63 -> t8 = 8
+	// e tm = 0x7F)
+	t8 := (t1 + 7) / 8
+	tm := byte(0xFF >> (8*t8 - t1))
+	L[128-t8] = piTable[L[128-t8]&tm]
+	for i := 127 - t8; i >= 0; i-- {
+		L[i] = piTable[L[i+1]^L[i+t8]]
+	}
+
+	var K [64]uint16
+	for i := range K {
+		K[i] = uint16(L[2*i]) | uint16(L[2*i+1])<<8
+	}
+	return K
+}
+
+// Deslocamentos das rotações do MIX para R[0..3].
+var mixShifts = [4]int{1, 2, 3, 5}
+
+func (c *RC2) mix(R *[4]uint16, j *int) {
+	for i := 0; i < 4; i++ {
+		R[i] += c.K[*j] + (R[(i+3)%4] & R[(i+2)%4]) + (^R[(i+3)%4] & R[(i+1)%4])
+		R[i] = bits.RotateLeft16(R[i], mixShifts[i])
+		*j++
+	}
+}
+
+func (c *RC2) mash(R *[4]uint16) {
+	for i := 0; i < 4; i++ {
+		R[i] += c.K[R[(i+3)%4]&63]
+	}
+}
+
+func (c *RC2) rmix(R *[4]uint16, j *int) {
+	for i := 3; i >= 0; i-- {
+		R[i] = bits.RotateLeft16(R[i], -mixShifts[i])
+		R[i] -= c.K[*j] + (R[(i+3)%4] & R[(i+2)%4]) + (^R[(i+3)%4] & R[(i+1)%4])
+		*j--
+	}
+}
+
+func (c *RC2) rmash(R *[4]uint16) {
+	for i := 3; i >= 0; i-- {
+		R[i] -= c.K[R[(i+3)%4]&63]
+	}
+}
+
+func load(R *[4]uint16, src []byte) {
+	if len(src) < BlockSize {
+		panic("rc2: bloco de entrada incompleto")
+	}
+	for i := range R {
+		R[i] = binary.LittleEndian.Uint16(src[2*i:])
+	}
+}
+
+func store(dst []byte, R *[4]uint16) {
+	if len(dst) < BlockSize {
+		panic("rc2: bloco de saída incompleto")
+	}
+	for i := range R {
+		binary.LittleEndian.PutUint16(dst[2*i:], R[i])
+	}
+}
+
+func (c *RC2) BlockSize() int { return BlockSize }
+
+func (c *RC2) Encrypt(dst, src []byte) {
+	var R [4]uint16
+	load(&R, src)
+
+	j := 0
+	for round := 0; round < 16; round++ {
+		c.mix(&R, &j)
+		if round == 4 || round == 10 {
+			c.mash(&R)
+		}
+	}
+
+	store(dst, &R)
+}
+
+func (c *RC2) Decrypt(dst, src []byte) {
+	var R [4]uint16
+	load(&R, src)
+
+	j := 63
+	for round := 15; round >= 0; round-- {
+		c.rmix(&R, &j)
+		if round == 5 || round == 11 {
+			c.rmash(&R)
+		}
+	}
+
+	store(dst, &R)
+}
+
+// EncryptBlock cifra um bloco de 8 bytes e devolve um slice novo.
+func (c *RC2) EncryptBlock(block []byte) []byte {
+	out := make([]byte, BlockSize)
+	c.Encrypt(out, block)
+	return out
+}
+
+// DecryptBlock decifra um bloco de 8 bytes e devolve um slice novo.
+func (c *RC2) DecryptBlock(block []byte) []byte {
+	out := make([]byte, BlockSize)
+	c.Decrypt(out, block)
+	return out
+}
+
+// testVector é um vetor da RFC 2268, seção 5 (valores em hexadecimal).
+type testVector struct {
+	key        string
+	t1         int
+	plaintext  string
+	ciphertext string
+}
+
+var rfc2268Vectors = []testVector{
+	{"0000000000000000", 63, "0000000000000000", "ebb773f993278eff"},
+	{"ffffffffffffffff", 64, "ffffffffffffffff", "278b27e42e2f0d49"},
+	{"3000000000000000", 64, "1000000000000001", "30649edf9be7d2c2"},
+	{"88", 64, "0000000000000000", "61a8a244adacccf0"},
+	{"88bca90e90875a", 64, "0000000000000000", "6ccf4308974c267f"},
+	{"88bca90e90875a7f0f79c384627bafb2", 64, "0000000000000000", "1a807d272bbe5db1"},
+	{"88bca90e90875a7f0f79c384627bafb2", 128, "0000000000000000", "2269552ab0f85ca6"},
+	{"88bca90e90875a7f0f79c384627bafb216f80a6f85920584c42fceb0be255daf1e", 129, "0000000000000000", "5b78d3a43dfff1f1"},
+}
+
+func main() {
+	// Confere os vetores da RFC 2268 cifrando e decifrando cada um
+	for _, v := range rfc2268Vectors {
+		key, _ := hex.DecodeString(v.key)
+		plaintext, _ := hex.DecodeString(v.plaintext)
+
+		rc2, err := New(key, v.t1)
+		if err != nil {
+			panic(err)
+		}
+		ciphertext := rc2.EncryptBlock(plaintext)
+		decrypted := rc2.DecryptBlock(ciphertext)
+
+		status := "ok"
+		if hex.EncodeToString(ciphertext) != v.ciphertext || hex.EncodeToString(decrypted) != v.plaintext {
+			status = "ERRO"
+		}
+		fmt.Printf("chave %-66s T1 = %4d: %x -> %x  %s\n", v.key, v.t1, plaintext, ciphertext, status)
+	}
+}
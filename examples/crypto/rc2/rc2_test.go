@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestRFC2268Vectors(t *testing.T) {
+	for _, v := range rfc2268Vectors {
+		key := mustHex(t, v.key)
+		plaintext := mustHex(t, v.plaintext)
+		want := mustHex(t, v.ciphertext)
+
+		c, err := New(key, v.t1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := make([]byte, BlockSize)
+		c.Encrypt(got, plaintext)
+		if !bytes.Equal(got, want) {
+			t.Errorf("chave %s, T1 = %d: cifrado %x, esperado %x", v.key, v.t1, got, want)
+		}
+		c.Decrypt(got, got)
+		if !bytes.Equal(got, plaintext) {
+			t.Errorf("chave %s, T1 = %d: decifrado %x, esperado %x", v.key, v.t1, got, plaintext)
+		}
+	}
+}
+
+func TestPiTableIsPermutation(t *testing.T) {
+	var seen [256]bool
+	for _, v := range piTable {
+		if seen[v] {
+			t.Fatalf("valor %d repetido na PITABLE", v)
+		}
+		seen[v] = true
+	}
+}
+
+func TestInvalidParameters(t *testing.T) {
+	var ks KeySizeError
+	for _, n := range []int{0, 129} {
+		if _, err := New(make([]byte, n), 64); !errors.As(err, &ks) {
+			t.Errorf("chave de %d bytes: erro %v, esperado KeySizeError", n, err)
+		}
+	}
+	var eb EffectiveBitsError
+	for _, t1 := range []int{0, 1025} {
+		if _, err := New(make([]byte, 8), t1); !errors.As(err, &eb) {
+			t.Errorf("T1 = %d: erro %v, esperado EffectiveBitsError", t1, err)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		key := make([]byte, 1+rng.Intn(128))
+		rng.Read(key)
+		c, err := New(key, 1+rng.Intn(1024))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		block := make([]byte, BlockSize)
+		rng.Read(block)
+		if got := c.DecryptBlock(c.EncryptBlock(block)); !bytes.Equal(got, block) {
+			t.Fatalf("ida e volta: %x, esperado %x", got, block)
+		}
+	}
+}
@@ -0,0 +1,3 @@
+module github.com/osdeving/rc5
+
+go 1.24.2
@@ -0,0 +1,272 @@
+/*
+	RC5-w/r/b (Rivest, 1994; RFC 2040)
+
+	Cifra de bloco parametrizada:
+
+		w = tamanho da palavra em bits (16, 32 ou 64); o bloco tem 2w bits
+		r = número de rodadas (0 a 255); Rivest sugeria 12 para w = 32
+		b = tamanho da chave em bytes (0 a 255)
+
+	O bloco são duas palavras A e B (little-endian). A única não
+	linearidade vem das rotações dependentes dos dados:
+
+		A = A + S[0]
+		B = B + S[1]
+		para i = 1..r:
+			A = ((A XOR B) <<< B) + S[2i]
+			B = ((B XOR A) <<< A) + S[2i+1]
+
+	Só os lg(w) bits menos significativos da quantidade de rotação contam
+	(B mod w). A decifragem desfaz cada passo na ordem inversa: subtrai a
+	subchave, rotaciona à direita e faz o XOR.
+
+	Expansão de chave com constantes mágicas:
+
+		Pw = Odd((e - 2) * 2^w)     Qw = Odd((φ - 1) * 2^w)
+
+		+----+--------------------+--------------------+
+		|  w | Pw                 | Qw                 |
+		+----+--------------------+--------------------+
+		| 16 | b7e1               | 9e37               |
+		| 32 | b7e15163           | 9e3779b9           |
+		| 64 | b7e151628aed2a6b   | 9e3779b97f4a7c15   |
+		+----+--------------------+--------------------+
+
+		1. A chave vira c = max(1, ceil(b/u)) palavras L[] (u = w/8 bytes)
+		2. S[0] = Pw, S[i] = S[i-1] + Qw, para t = 2r+2 palavras
+		3. 3*max(t, c) passos misturam L em S:
+			A = S[i] = (S[i] + A + B) <<< 3
+			B = L[j] = (L[j] + A + B) <<< (A + B)
+
+	As constantes são "nothing up my sleeve": vêm de e e da razão áurea,
+	não escondem estrutura. O RC6 (rc6.go) usa a mesma expansão com
+	t = 2r+4.
+*/
+
+package main
+
+import (
+	"crypto/cipher"
+	"encoding/hex"
+	"fmt"
+)
+
+// KeySizeError indica uma chave com mais de 255 bytes.
+type KeySizeError int
+
+func (k KeySizeError) Error() string {
+	return fmt.Sprintf("rc5: tamanho de chave inválido: %d bytes (use 0 a 255)", int(k))
+}
+
+// RoundsError indica um número de rodadas fora de 0 a 255.
+type RoundsError int
+
+func (r RoundsError) Error() string {
+	return fmt.Sprintf("rc5: número de rodadas inválido: %d (use 0 a 255)", int(r))
+}
+
+// WordSizeError indica uma palavra diferente de 16, 32 ou 64 bits.
+type WordSizeError int
+
+func (w WordSizeError) Error() string {
+	return fmt.Sprintf("rc5: tamanho de palavra inválido: %d bits (use 16, 32 ou 64)", int(w))
+}
+
+// Constantes mágicas Pw e Qw por tamanho de palavra.
+var magic = map[int][2]uint64{
+	16: {0xb7e1, 0x9e37},
+	32: {0xb7e15163, 0x9e3779b9},
+	64: {0xb7e151628aed2a6b, 0x9e3779b97f4a7c15},
+}
+
+/*
+RC5 guarda os parâmetros e a tabela S expandida. As palavras ficam em
+uint64 e mask as reduz a w bits depois de cada operação.
+*/
+type RC5 struct {
+	w, r int
+	mask uint64
+	S    []uint64
+}
+
+var _ cipher.Block = (*RC5)(nil)
+
+// New: Cria o RC5-w/r/b, com b = len(key).
+func New(key []byte, w, r int) (*RC5, error) {
+	if _, ok := magic[w]; !ok {
+		return nil, WordSizeError(w)
+	}
+	if r < 0 || r > 255 {
+		return nil, RoundsError(r)
+	}
+	if len(key) > 255 {
+		return nil, KeySizeError(len(key))
+	}
+	return &RC5{w: w, r: r, mask: wordMask(w), S: expandKey(key, w, 2*r+2)}, nil
+}
+
+func wordMask(w int) uint64 { return ^uint64(0) >> (64 - w) }
+
+// rotl rotaciona x (de w bits) n posições à esquerda; só n mod w conta.
+func rotl(x, n uint64, w int) uint64 {
+	s := n % uint64(w)
+	return (x<<s | x>>(uint64(w)-s)) & wordMask(w)
+}
+
+// rotr rotaciona x (de w bits) n posições à direita.
+func rotr(x, n uint64, w int) uint64 {
+	s := n % uint64(w)
+	return (x>>s | x<<(uint64(w)-s)) & wordMask(w)
+}
+
+// expandKey: Expansão de chave do RC5 com t palavras de w bits.
+func expandKey(key []byte, w, t int) []uint64 {
+	u := w / 8
+	mask := wordMask(w)
+
+	// 1. Chave em palavras little-endian (a última completada com zeros)
+	c := max(1, (len(key)+u-1)/u)
+	L := make([]uint64, c)
+	for i := len(key) - 1; i >= 0; i-- {
+		L[i/u] = L[i/u]<<8 | uint64(key[i])
+	}
+
+	// 2. Tabela inicial a partir das constantes mágicas
+	S := make([]uint64, t)
+	S[0] = magic[w][0]
+	for i := 1; i < t; i++ {
+		S[i] = (S[i-1] + magic[w][1]) & mask
+	}
+
+	// 3. Mistura: cada palavra de S e de L é atualizada pelo menos 3 vezes
+	var A, B uint64
+	i, j := 0, 0
+	for k := 0; k < 3*max(t, c); k++ {
+		A = rotl((S[i]+A+B)&mask, 3, w)
+		S[i] = A
+		B = rotl((L[j]+A+B)&mask, A+B, w)
+		L[j] = B
+		i = (i + 1) % t
+		j = (j + 1) % c
+	}
+	return S
+}
+
+// loadWord lê uma palavra little-endian de len(b) bytes.
+func loadWord(b []byte) uint64 {
+	var x uint64
+	for i := len(b) - 1; i >= 0; i-- {
+		x = x<<8 | uint64(b[i])
+	}
+	return x
+}
+
+// storeWord escreve x em little-endian nos len(b) bytes de b.
+func storeWord(b []byte, x uint64) {
+	for i := range b {
+		b[i] = byte(x >> (8 * i))
+	}
+}
+
+// Params devolve o tamanho da palavra e o número de rodadas.
+func (c *RC5) Params() (w, r int) { return c.w, c.r }
+
+func (c *RC5) BlockSize() int { return c.w / 4 }
+
+func (c *RC5) Encrypt(dst, src []byte) {
+	u := c.w / 8
+	if len(src) < 2*u || len(dst) < 2*u {
+		panic("rc5: bloco incompleto")
+	}
+
+	A := (loadWord(src[:u]) + c.S[0]) & c.mask
+	B := (loadWord(src[u:2*u]) + c.S[1]) & c.mask
+	for i := 1; i <= c.r; i++ {
+		A = (rotl(A^B, B, c.w) + c.S[2*i]) & c.mask
+		B = (rotl(B^A, A, c.w) + c.S[2*i+1]) & c.mask
+	}
+
+	storeWord(dst[:u], A)
+	storeWord(dst[u:2*u], B)
+}
+
+func (c *RC5) Decrypt(dst, src []byte) {
+	u := c.w / 8
+	if len(src) < 2*u || len(dst) < 2*u {
+		panic("rc5: bloco incompleto")
+	}
+
+	A := loadWord(src[:u])
+	B := loadWord(src[u : 2*u])
+	for i := c.r; i >= 1; i-- {
+		B = rotr((B-c.S[2*i+1])&c.mask, A, c.w) ^ A
+		A = rotr((A-c.S[2*i])&c.mask, B, c.w) ^ B
+	}
+	B = (B - c.S[1]) & c.mask
+	A = (A - c.S[0]) & c.mask
+
+	storeWord(dst[:u], A)
+	storeWord(dst[u:2*u], B)
+}
+
+// testVector é um vetor publicado (valores em hexadecimal).
+type testVector struct {
+	w, r       int
+	key        string
+	plaintext  string
+	ciphertext string
+}
+
+/*
+rc5Vectors: Os cinco vetores do artigo de Rivest ("The RC5 Encryption
+Algorithm", RC5-32/12/16, cada plaintext é o ciphertext anterior) e os de
+draft-krovetz-rc6-rc5-vectors para 16, 32 e 64 bits, com chave e
+plaintext 00 01 02 ...
+*/
+var rc5Vectors = []testVector{
+	{32, 12, "00000000000000000000000000000000", "0000000000000000", "21a5dbee154b8f6d"},
+	{32, 12, "915f4619be41b2516355a50110a9ce91", "21a5dbee154b8f6d", "f7c013ac5b2b8952"},
+	{32, 12, "783348e75aeb0f2fd7b169bb8dc16787", "f7c013ac5b2b8952", "2f42b3b70369fc92"},
+	{32, 12, "dc49db1375a5584f6485b413b5f12baf", "2f42b3b70369fc92", "65c178b284d197cc"},
+	{32, 12, "5269f149d41ba0152497574d7f153125", "65c178b284d197cc", "eb44e415da319824"},
+	{16, 16, "0001020304050607", "00010203", "23a8d72e"},
+	{32, 20, "000102030405060708090a0b0c0d0e0f", "0001020304050607", "2a0edc0e9431ff73"},
+	{64, 24, "000102030405060708090a0b0c0d0e0f1011121314151617", "000102030405060708090a0b0c0d0e0f", "a46772820edbce0235abea32ae7178da"},
+}
+
+func main() {
+	fmt.Println("RC5")
+	for _, v := range rc5Vectors {
+		key, _ := hex.DecodeString(v.key)
+		c, err := New(key, v.w, v.r)
+		if err != nil {
+			panic(err)
+		}
+		check(c, fmt.Sprintf("RC5-%d/%d/%d", v.w, v.r, len(key)), v)
+	}
+
+	fmt.Println("\nRC6")
+	for _, v := range rc6Vectors {
+		key, _ := hex.DecodeString(v.key)
+		c, err := NewRC6(key)
+		if err != nil {
+			panic(err)
+		}
+		check(c, fmt.Sprintf("RC6-32/20/%d", len(key)), v)
+	}
+}
+
+// check cifra e decifra o vetor e imprime o resultado.
+func check(c cipher.Block, name string, v testVector) {
+	plaintext, _ := hex.DecodeString(v.plaintext)
+	ciphertext := make([]byte, c.BlockSize())
+	c.Encrypt(ciphertext, plaintext)
+	decrypted := make([]byte, c.BlockSize())
+	c.Decrypt(decrypted, ciphertext)
+
+	status := "ok"
+	if hex.EncodeToString(ciphertext) != v.ciphertext || hex.EncodeToString(decrypted) != v.plaintext {
+		status = "ERRO"
+	}
+	fmt.Printf("  %-13s %x -> %x  %s\n", name, plaintext, ciphertext, status)
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestRC5Vectors(t *testing.T) {
+	for _, v := range rc5Vectors {
+		key := mustHex(t, v.key)
+		plaintext := mustHex(t, v.plaintext)
+		want := mustHex(t, v.ciphertext)
+
+		c, err := New(key, v.w, v.r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.BlockSize() != len(plaintext) {
+			t.Fatalf("RC5-%d: bloco de %d bytes, esperado %d", v.w, c.BlockSize(), len(plaintext))
+		}
+		got := make([]byte, c.BlockSize())
+		c.Encrypt(got, plaintext)
+		if !bytes.Equal(got, want) {
+			t.Errorf("RC5-%d/%d chave %s: cifrado %x, esperado %x", v.w, v.r, v.key, got, want)
+		}
+		c.Decrypt(got, got)
+		if !bytes.Equal(got, plaintext) {
+			t.Errorf("RC5-%d/%d chave %s: decifrado %x, esperado %x", v.w, v.r, v.key, got, plaintext)
+		}
+	}
+}
+
+func TestRC5InvalidParameters(t *testing.T) {
+	var ws WordSizeError
+	for _, w := range []int{0, 8, 24, 128} {
+		if _, err := New(nil, w, 12); !errors.As(err, &ws) {
+			t.Errorf("w = %d: erro %v, esperado WordSizeError", w, err)
+		}
+	}
+	var rs RoundsError
+	for _, r := range []int{-1, 256} {
+		if _, err := New(nil, 32, r); !errors.As(err, &rs) {
+			t.Errorf("r = %d: erro %v, esperado RoundsError", r, err)
+		}
+	}
+	var ks KeySizeError
+	if _, err := New(make([]byte, 256), 32, 12); !errors.As(err, &ks) {
+		t.Errorf("chave de 256 bytes: erro %v, esperado KeySizeError", err)
+	}
+}
+
+func TestRC5RoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, w := range []int{16, 32, 64} {
+		for i := 0; i < 50; i++ {
+			key := make([]byte, rng.Intn(256))
+			rng.Read(key)
+			c, err := New(key, w, rng.Intn(256))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			block := make([]byte, c.BlockSize())
+			rng.Read(block)
+			got := make([]byte, c.BlockSize())
+			c.Encrypt(got, block)
+			c.Decrypt(got, got)
+			if !bytes.Equal(got, block) {
+				t.Fatalf("RC5-%d: ida e volta %x, esperado %x", w, got, block)
+			}
+		}
+	}
+}
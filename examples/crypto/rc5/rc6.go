@@ -0,0 +1,133 @@
+/*
+	RC6-32/20/b (Rivest, Robshaw, Sidney e Yin, 1998)
+
+	Finalista do AES, construído a partir do RC5: bloco de 128 bits em
+	quatro palavras de 32 bits A, B, C, D (little-endian), 20 rodadas e
+	chave de 16, 24 ou 32 bytes (o algoritmo aceita 0 a 255).
+
+		B = B + S[0]
+		D = D + S[1]
+		para i = 1..r:
+			t = (B * (2B + 1)) <<< 5
+			u = (D * (2D + 1)) <<< 5
+			A = ((A XOR t) <<< u) + S[2i]
+			C = ((C XOR u) <<< t) + S[2i+1]
+			(A, B, C, D) = (B, C, D, A)
+		A = A + S[2r+2]
+		C = C + S[2r+3]
+
+	Em relação ao RC5, a quantidade de rotação deixa de ser a própria
+	palavra: f(x) = x(2x+1) mod 2^32 é uma bijeção que espalha todos os
+	bits de x nos 5 bits mais significativos, e a rotação fixa de lg(w) = 5
+	os traz para a posição usada como rotação. As duas metades (A, B) e
+	(C, D) funcionam como dois RC5 em paralelo trocando as rotações.
+
+	A expansão de chave é a do RC5-32 (rc5.go) com t = 2r+4 palavras.
+*/
+
+package main
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+)
+
+const (
+	RC6BlockSize = 16
+	rc6Rounds    = 20
+)
+
+// RC6KeySizeError indica uma chave do RC6 com mais de 255 bytes.
+type RC6KeySizeError int
+
+func (k RC6KeySizeError) Error() string {
+	return fmt.Sprintf("rc6: tamanho de chave inválido: %d bytes (use 0 a 255)", int(k))
+}
+
+// RC6 guarda as 2r+4 subchaves do RC6-32/20/b.
+type RC6 struct {
+	S [2*rc6Rounds + 4]uint32
+}
+
+var _ cipher.Block = (*RC6)(nil)
+
+// NewRC6: Cria o RC6-32/20/b, com b = len(key) de 0 a 255.
+func NewRC6(key []byte) (*RC6, error) {
+	if len(key) > 255 {
+		return nil, RC6KeySizeError(len(key))
+	}
+	c := &RC6{}
+	for i, s := range expandKey(key, 32, len(c.S)) {
+		c.S[i] = uint32(s)
+	}
+	return c, nil
+}
+
+// rc6F é a função f(x) = (x(2x+1) mod 2^32) <<< 5.
+func rc6F(x uint32) uint32 { return bits.RotateLeft32(x*(2*x+1), 5) }
+
+func (c *RC6) BlockSize() int { return RC6BlockSize }
+
+func (c *RC6) Encrypt(dst, src []byte) {
+	if len(src) < RC6BlockSize || len(dst) < RC6BlockSize {
+		panic("rc6: bloco incompleto")
+	}
+	A := binary.LittleEndian.Uint32(src[0:])
+	B := binary.LittleEndian.Uint32(src[4:]) + c.S[0]
+	C := binary.LittleEndian.Uint32(src[8:])
+	D := binary.LittleEndian.Uint32(src[12:]) + c.S[1]
+
+	for i := 1; i <= rc6Rounds; i++ {
+		t := rc6F(B)
+		u := rc6F(D)
+		A = bits.RotateLeft32(A^t, int(u&31)) + c.S[2*i]
+		C = bits.RotateLeft32(C^u, int(t&31)) + c.S[2*i+1]
+		A, B, C, D = B, C, D, A
+	}
+	A += c.S[2*rc6Rounds+2]
+	C += c.S[2*rc6Rounds+3]
+
+	binary.LittleEndian.PutUint32(dst[0:], A)
+	binary.LittleEndian.PutUint32(dst[4:], B)
+	binary.LittleEndian.PutUint32(dst[8:], C)
+	binary.LittleEndian.PutUint32(dst[12:], D)
+}
+
+func (c *RC6) Decrypt(dst, src []byte) {
+	if len(src) < RC6BlockSize || len(dst) < RC6BlockSize {
+		panic("rc6: bloco incompleto")
+	}
+	A := binary.LittleEndian.Uint32(src[0:])
+	B := binary.LittleEndian.Uint32(src[4:])
+	C := binary.LittleEndian.Uint32(src[8:])
+	D := binary.LittleEndian.Uint32(src[12:])
+
+	C -= c.S[2*rc6Rounds+3]
+	A -= c.S[2*rc6Rounds+2]
+	for i := rc6Rounds; i >= 1; i-- {
+		A, B, C, D = D, A, B, C
+		u := rc6F(D)
+		t := rc6F(B)
+		C = bits.RotateLeft32(C-c.S[2*i+1], -int(t&31)) ^ u
+		A = bits.RotateLeft32(A-c.S[2*i], -int(u&31)) ^ t
+	}
+	D -= c.S[1]
+	B -= c.S[0]
+
+	binary.LittleEndian.PutUint32(dst[0:], A)
+	binary.LittleEndian.PutUint32(dst[4:], B)
+	binary.LittleEndian.PutUint32(dst[8:], C)
+	binary.LittleEndian.PutUint32(dst[12:], D)
+}
+
+// rc6Vectors: Vetores do artigo "The RC6 Block Cipher" (AES, 1998).
+var rc6Vectors = []testVector{
+	{32, 20, "00000000000000000000000000000000", "00000000000000000000000000000000", "8fc3a53656b1f778c129df4e9848a41e"},
+	{32, 20, "0123456789abcdef0112233445566778", "02132435465768798a9bacbdcedfe0f1", "524e192f4715c6231f51f6367ea43f18"},
+	{32, 20, "000000000000000000000000000000000000000000000000", "00000000000000000000000000000000", "6cd61bcb190b30384e8a3f168690ae82"},
+	{32, 20, "0123456789abcdef0112233445566778899aabbccddeeff0", "02132435465768798a9bacbdcedfe0f1", "688329d019e505041e52e92af95291d4"},
+	{32, 20, "0000000000000000000000000000000000000000000000000000000000000000", "00000000000000000000000000000000", "8f5fbd0510d15fa893fa3fda6e857ec2"},
+	{32, 20, "0123456789abcdef0112233445566778899aabbccddeeff01032547698badcfe", "02132435465768798a9bacbdcedfe0f1", "c8241816f0d7e48920ad16a1674e5d48"},
+}
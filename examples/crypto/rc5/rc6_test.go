@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestRC6Vectors(t *testing.T) {
+	for _, v := range rc6Vectors {
+		key := mustHex(t, v.key)
+		plaintext := mustHex(t, v.plaintext)
+		want := mustHex(t, v.ciphertext)
+
+		c, err := NewRC6(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := make([]byte, RC6BlockSize)
+		c.Encrypt(got, plaintext)
+		if !bytes.Equal(got, want) {
+			t.Errorf("chave %s: cifrado %x, esperado %x", v.key, got, want)
+		}
+		c.Decrypt(got, got)
+		if !bytes.Equal(got, plaintext) {
+			t.Errorf("chave %s: decifrado %x, esperado %x", v.key, got, plaintext)
+		}
+	}
+}
+
+func TestRC6InvalidKey(t *testing.T) {
+	var ks RC6KeySizeError
+	_, err := NewRC6(make([]byte, 256))
+	if !errors.As(err, &ks) {
+		t.Fatalf("chave de 256 bytes: erro %v, esperado RC6KeySizeError", err)
+	}
+	if !strings.HasPrefix(err.Error(), "rc6: ") {
+		t.Errorf("mensagem %q sem o prefixo rc6", err)
+	}
+}
+
+func TestRC6RoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		key := make([]byte, rng.Intn(256))
+		rng.Read(key)
+		c, err := NewRC6(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		block := make([]byte, RC6BlockSize)
+		rng.Read(block)
+		got := make([]byte, RC6BlockSize)
+		c.Encrypt(got, block)
+		c.Decrypt(got, got)
+		if !bytes.Equal(got, block) {
+			t.Fatalf("ida e volta %x, esperado %x", got, block)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+/*
+
+S-AES: Simplified AES (Musa, Schaefer e Wedig, 2003)
+
+Versão de brinquedo do AES para estudo manual. A estrutura é a mesma de
+aes.go, mas tudo encolhe:
+
+	+-----------------+----------------------------+-------------------------------+
+	|                 | AES                        | S-AES                         |
+	+-----------------+----------------------------+-------------------------------+
+	| bloco / chave   | 128 / 128-256 bits         | 16 / 16 bits                  |
+	| state           | 4x4 bytes                  | 2x2 nibbles                   |
+	| corpo           | GF(2^8), x^8+x^4+x^3+x+1   | GF(2^4), x^4+x+1              |
+	| S-box           | 8 -> 8 bits                | 4 -> 4 bits                   |
+	| ShiftRows       | linha i gira i posições    | troca os nibbles da linha 1   |
+	| MixColumns      | [2 3 1 1] circulante       | [1 4; 4 1]                    |
+	| rodadas         | 10, 12 ou 14               | 2                             |
+	+-----------------+----------------------------+-------------------------------+
+
+	Cifragem:  AddRoundKey(K0)
+	           rodada 1: SubNibbles, ShiftRows, MixColumns, AddRoundKey(K1)
+	           rodada 2: SubNibbles, ShiftRows, AddRoundKey(K2)
+
+O state é preenchido coluna a coluna, como no AES: o bloco n0 n1 n2 n3
+(n0 = nibble mais significativo) vira
+
+	| n0 n2 |
+	| n1 n3 |
+
+O Mini-AES de Phan (2002) é parecido (também 16 bits e GF(2^4)), mas usa
+outra S-box, a matriz [3 2; 2 3] e chave de rodada diferente.
+
+*/
+
+package main
+
+import "fmt"
+
+// State é a matriz 2x2 de nibbles (cada byte guarda só 4 bits).
+type State [2][2]byte
+
+// Polinômio redutor de GF(2^4): x^4 + x + 1
+const poly = 0x13
+
+var sbox = [16]byte{
+	0x9, 0x4, 0xA, 0xB,
+	0xD, 0x1, 0x8, 0x5,
+	0x6, 0x2, 0x0, 0x3,
+	0xC, 0xE, 0xF, 0x7,
+}
+
+var invSbox = [16]byte{
+	0xA, 0x5, 0x9, 0xB,
+	0x1, 0x7, 0x8, 0xF,
+	0x6, 0x0, 0x2, 0x3,
+	0xC, 0x4, 0xD, 0xE,
+}
+
+// SAES guarda as três chaves de rodada de 16 bits.
+type SAES struct {
+	roundKeys [3]uint16
+}
+
+func New(key uint16) *SAES {
+	return &SAES{roundKeys: KeyExpansion(key)}
+}
+
+// gfMul multiplica dois nibbles em GF(2^4).
+func gfMul(a, b byte) byte {
+	var res byte
+	for b > 0 {
+		if b&1 != 0 {
+			res ^= a
+		}
+		a <<= 1
+		if a&0x10 != 0 {
+			a ^= poly
+		}
+		b >>= 1
+	}
+	return res & 0xF
+}
+
+func toState(block uint16) State {
+	return State{
+		{byte(block >> 12), byte(block>>4) & 0xF},
+		{byte(block>>8) & 0xF, byte(block) & 0xF},
+	}
+}
+
+func (s State) Uint16() uint16 {
+	return uint16(s[0][0])<<12 | uint16(s[1][0])<<8 | uint16(s[0][1])<<4 | uint16(s[1][1])
+}
+
+func SubNibbles(state *State) {
+	for row := 0; row < 2; row++ {
+		for col := 0; col < 2; col++ {
+			state[row][col] = sbox[state[row][col]]
+		}
+	}
+}
+
+func InvSubNibbles(state *State) {
+	for row := 0; row < 2; row++ {
+		for col := 0; col < 2; col++ {
+			state[row][col] = invSbox[state[row][col]]
+		}
+	}
+}
+
+// ShiftRows troca os dois nibbles da linha 1; é a própria inversa.
+func ShiftRows(state *State) {
+	state[1][0], state[1][1] = state[1][1], state[1][0]
+}
+
+func MixColumns(state *State) {
+	for col := 0; col < 2; col++ {
+		s0, s1 := state[0][col], state[1][col]
+		state[0][col] = s0 ^ gfMul(4, s1)
+		state[1][col] = gfMul(4, s0) ^ s1
+	}
+}
+
+func InvMixColumns(state *State) {
+	for col := 0; col < 2; col++ {
+		s0, s1 := state[0][col], state[1][col]
+		state[0][col] = gfMul(9, s0) ^ gfMul(2, s1)
+		state[1][col] = gfMul(2, s0) ^ gfMul(9, s1)
+	}
+}
+
+func AddRoundKey(state *State, roundKey uint16) {
+	k := toState(roundKey)
+	for row := 0; row < 2; row++ {
+		for col := 0; col < 2; col++ {
+			state[row][col] ^= k[row][col]
+		}
+	}
+}
+
+// RotNib troca os nibbles de uma palavra de 8 bits.
+func RotNib(w byte) byte {
+	return w<<4 | w>>4
+}
+
+func SubNib(w byte) byte {
+	return sbox[w>>4]<<4 | sbox[w&0xF]
+}
+
+// Constantes de rodada: x^3 = 1000 e x^4 = 0011 em GF(2^4), no nibble alto
+var rcon = [2]byte{0x80, 0x30}
+
+/*
+KeyExpansion: Gera 6 palavras de 8 bits (3 chaves de rodada de 16 bits).
+
+	w0 w1 = chave
+	w2 = w0 ^ RCON(1) ^ SubNib(RotNib(w1))    w3 = w2 ^ w1
+	w4 = w2 ^ RCON(2) ^ SubNib(RotNib(w3))    w5 = w4 ^ w3
+*/
+func KeyExpansion(key uint16) [3]uint16 {
+	var w [6]byte
+	w[0], w[1] = byte(key>>8), byte(key)
+	for i := 2; i < 6; i += 2 {
+		w[i] = w[i-2] ^ rcon[i/2-1] ^ SubNib(RotNib(w[i-1]))
+		w[i+1] = w[i] ^ w[i-1]
+	}
+	return [3]uint16{
+		uint16(w[0])<<8 | uint16(w[1]),
+		uint16(w[2])<<8 | uint16(w[3]),
+		uint16(w[4])<<8 | uint16(w[5]),
+	}
+}
+
+func (s *SAES) Encrypt(plaintext uint16) uint16 {
+	state := toState(plaintext)
+
+	AddRoundKey(&state, s.roundKeys[0])
+
+	SubNibbles(&state)
+	ShiftRows(&state)
+	MixColumns(&state)
+	AddRoundKey(&state, s.roundKeys[1])
+
+	SubNibbles(&state)
+	ShiftRows(&state)
+	AddRoundKey(&state, s.roundKeys[2])
+
+	return state.Uint16()
+}
+
+func (s *SAES) Decrypt(ciphertext uint16) uint16 {
+	state := toState(ciphertext)
+
+	AddRoundKey(&state, s.roundKeys[2])
+	ShiftRows(&state)
+	InvSubNibbles(&state)
+
+	AddRoundKey(&state, s.roundKeys[1])
+	InvMixColumns(&state)
+	ShiftRows(&state)
+	InvSubNibbles(&state)
+
+	AddRoundKey(&state, s.roundKeys[0])
+
+	return state.Uint16()
+}
+
+func main() {
+	// Exemplo de Stallings, Cryptography and Network Security, apêndice do S-AES
+	var key uint16 = 0xA73B
+	var plaintext uint16 = 0x6F6B
+
+	s := New(key)
+	ciphertext := s.Encrypt(plaintext)
+	fmt.Printf("Chaves de rodada: %04x\n", s.roundKeys)
+	fmt.Printf("Cifrado:   %04x\n", ciphertext)
+	fmt.Printf("Decifrado: %04x\n", s.Decrypt(ciphertext))
+
+	// Busca exaustiva com 2 pares conhecidos
+	pairs := []Pair{{plaintext, ciphertext}, {0x1234, s.Encrypt(0x1234)}}
+	fmt.Printf("Busca exaustiva: %04x\n", ExhaustiveSearch(pairs))
+
+	// Meet-in-the-middle contra S-AES duplo (chave de 32 bits)
+	k1, k2 := uint16(0x2D55), uint16(0x4AF5)
+	var double []Pair
+	for _, p := range []uint16{0x6F6B, 0x1234, 0xBEEF} {
+		double = append(double, Pair{p, DoubleEncrypt(k1, k2, p)})
+	}
+	fmt.Printf("Meet-in-the-middle: %04x\n", MeetInTheMiddle(double))
+}
@@ -0,0 +1,94 @@
+/*
+
+Ataques ao S-AES
+
+Com chave de 16 bits, testar as 65536 chaves leva milissegundos. Um par
+(P, C) de 16 bits deixa em média 65536/65536 = 1 chave falsa além da
+verdadeira, então são necessários 2 pares para isolar a chave.
+
+S-AES duplo, C = E_k2(E_k1(P)), tem chave de 32 bits, mas o
+meet-in-the-middle reduz o custo de 2^32 para 2 * 2^16 cifragens:
+
+	1. para todo k1: tabela[E_k1(P)] += k1         (2^16 cifragens)
+	2. para todo k2: para k1 em tabela[D_k2(C)]:    (2^16 decifragens)
+	       candidato (k1, k2)
+	3. filtra os candidatos com os demais pares
+
+Cada valor intermediário de 16 bits aparece para ~1 k1, logo o passo 2
+gera ~2^16 candidatos; cada par extra divide esse número por 2^16.
+
+*/
+
+package main
+
+// Pair é um par conhecido de plaintext e ciphertext.
+type Pair struct {
+	Plaintext  uint16
+	Ciphertext uint16
+}
+
+// ExhaustiveSearch devolve todas as chaves compatíveis com os pares.
+func ExhaustiveSearch(pairs []Pair) []uint16 {
+	var keys []uint16
+	for k := 0; k < 1<<16; k++ {
+		s := New(uint16(k))
+		if matches(pairs, s.Encrypt) {
+			keys = append(keys, uint16(k))
+		}
+	}
+	return keys
+}
+
+func matches(pairs []Pair, encrypt func(uint16) uint16) bool {
+	for _, p := range pairs {
+		if encrypt(p.Plaintext) != p.Ciphertext {
+			return false
+		}
+	}
+	return true
+}
+
+// DoubleEncrypt calcula E_k2(E_k1(p)).
+func DoubleEncrypt(k1, k2, p uint16) uint16 {
+	return New(k2).Encrypt(New(k1).Encrypt(p))
+}
+
+/*
+MeetInTheMiddle: Recupera os pares de chaves (k1, k2) do S-AES duplo
+compatíveis com todos os pares dados. O primeiro par monta a tabela e o
+encontro no meio; os demais só filtram.
+*/
+func MeetInTheMiddle(pairs []Pair) [][2]uint16 {
+	if len(pairs) == 0 {
+		return nil
+	}
+	first := pairs[0]
+
+	// O valor intermediário tem 16 bits: a "tabela hash" é um slice indexado
+	// por ele, com a lista de k1 que o produzem.
+	table := make([][]uint16, 1<<16)
+	ciphers := make([]*SAES, 1<<16)
+	for k := 0; k < 1<<16; k++ {
+		ciphers[k] = New(uint16(k))
+		mid := ciphers[k].Encrypt(first.Plaintext)
+		table[mid] = append(table[mid], uint16(k))
+	}
+
+	var keys [][2]uint16
+	for k2 := 0; k2 < 1<<16; k2++ {
+		mid := ciphers[k2].Decrypt(first.Ciphertext)
+		for _, k1 := range table[mid] {
+			ok := true
+			for _, p := range pairs[1:] {
+				if ciphers[k2].Encrypt(ciphers[k1].Encrypt(p.Plaintext)) != p.Ciphertext {
+					ok = false
+					break
+				}
+			}
+			if ok {
+				keys = append(keys, [2]uint16{k1, uint16(k2)})
+			}
+		}
+	}
+	return keys
+}
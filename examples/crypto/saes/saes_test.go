@@ -0,0 +1,73 @@
+package main
+
+import "testing"
+
+func TestSAESVetores(t *testing.T) {
+	tests := []struct {
+		key, plaintext, ciphertext uint16
+	}{
+		// Stallings, Cryptography and Network Security, apêndice do S-AES
+		{0xA73B, 0x6F6B, 0x0738},
+		// Musa, Schaefer e Wedig (2003), exemplo do artigo
+		{0x4AF5, 0xD728, 0x24EC},
+	}
+
+	for _, tt := range tests {
+		s := New(tt.key)
+		if got := s.Encrypt(tt.plaintext); got != tt.ciphertext {
+			t.Errorf("chave %04x: cifrado %04x, esperado %04x", tt.key, got, tt.ciphertext)
+		}
+		if got := s.Decrypt(tt.ciphertext); got != tt.plaintext {
+			t.Errorf("chave %04x: decifrado %04x, esperado %04x", tt.key, got, tt.plaintext)
+		}
+	}
+}
+
+func TestKeyExpansion(t *testing.T) {
+	want := [3]uint16{0xA73B, 0x1C27, 0x7651}
+	if got := KeyExpansion(0xA73B); got != want {
+		t.Errorf("chaves de rodada %04x, esperado %04x", got, want)
+	}
+}
+
+func TestSboxInversa(t *testing.T) {
+	for x := 0; x < 16; x++ {
+		if invSbox[sbox[x]] != byte(x) {
+			t.Errorf("invSbox[sbox[%x]] = %x", x, invSbox[sbox[x]])
+		}
+	}
+}
+
+func TestIdaEVolta(t *testing.T) {
+	for _, key := range []uint16{0x0000, 0xFFFF, 0x2D55} {
+		s := New(key)
+		for p := 0; p < 1<<16; p++ {
+			if got := s.Decrypt(s.Encrypt(uint16(p))); got != uint16(p) {
+				t.Fatalf("chave %04x: D(E(%04x)) = %04x", key, p, got)
+			}
+		}
+	}
+}
+
+func TestExhaustiveSearch(t *testing.T) {
+	s := New(0x2D55)
+	pairs := []Pair{{0x6F6B, s.Encrypt(0x6F6B)}, {0x0001, s.Encrypt(0x0001)}}
+
+	keys := ExhaustiveSearch(pairs)
+	if len(keys) != 1 || keys[0] != 0x2D55 {
+		t.Errorf("chaves encontradas %04x, esperado [2d55]", keys)
+	}
+}
+
+func TestMeetInTheMiddle(t *testing.T) {
+	k1, k2 := uint16(0xA73B), uint16(0x4AF5)
+	var pairs []Pair
+	for _, p := range []uint16{0x6F6B, 0xD728, 0x0000} {
+		pairs = append(pairs, Pair{p, DoubleEncrypt(k1, k2, p)})
+	}
+
+	keys := MeetInTheMiddle(pairs)
+	if len(keys) != 1 || keys[0] != [2]uint16{k1, k2} {
+		t.Errorf("chaves encontradas %04x, esperado [%04x %04x]", keys, k1, k2)
+	}
+}
@@ -0,0 +1,3 @@
+module github.com/osdeving/sboxanalysis
+
+go 1.24.2
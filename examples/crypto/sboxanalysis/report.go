@@ -0,0 +1,100 @@
+package sboxanalysis
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Report reúne as propriedades de uma S-box para impressão.
+type Report struct {
+	Name                     string
+	In, Out                  int
+	Permutation              bool
+	DifferentialUniformity   int
+	MaxDifferentialProb      float64
+	Nonlinearity             int
+	MaxLinearBias            float64
+	Degrees                  []int
+	FixedPoints              []int
+	OppositeFixedPoints      []int
+	DifferentialBranchNumber int
+	LinearBranchNumber       int
+	Avalanche                []float64
+	SACDeviation             float64
+}
+
+// Analyze calcula todas as propriedades de s.
+func Analyze(name string, s *SBox) Report {
+	size := float64(int(1) << s.In)
+	nl := s.Nonlinearity()
+	du := s.DifferentialUniformity()
+	return Report{
+		Name:                     name,
+		In:                       s.In,
+		Out:                      s.Out,
+		Permutation:              s.IsPermutation(),
+		DifferentialUniformity:   du,
+		MaxDifferentialProb:      float64(du) / size,
+		Nonlinearity:             nl,
+		MaxLinearBias:            (size/2 - float64(nl)) / size,
+		Degrees:                  s.Degrees(),
+		FixedPoints:              s.FixedPoints(),
+		OppositeFixedPoints:      s.OppositeFixedPoints(),
+		DifferentialBranchNumber: s.DifferentialBranchNumber(),
+		LinearBranchNumber:       s.LinearBranchNumber(),
+		Avalanche:                s.Avalanche(),
+		SACDeviation:             s.SACDeviation(),
+	}
+}
+
+// WriteText imprime o relatório em texto, uma propriedade por linha.
+func (r Report) WriteText(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "== %s (%d -> %d bits) ==\n", r.Name, r.In, r.Out)
+	fmt.Fprintf(&b, "  %-28s %v\n", "permutação", r.Permutation)
+	fmt.Fprintf(&b, "  %-28s %d (p = %.4f)\n", "uniformidade diferencial", r.DifferentialUniformity, r.MaxDifferentialProb)
+	fmt.Fprintf(&b, "  %-28s %d (viés máx = %.4f)\n", "não linearidade", r.Nonlinearity, r.MaxLinearBias)
+	fmt.Fprintf(&b, "  %-28s %v\n", "grau algébrico por bit", r.Degrees)
+	fmt.Fprintf(&b, "  %-28s %s\n", "pontos fixos", formatPoints(r.FixedPoints))
+	fmt.Fprintf(&b, "  %-28s %s\n", "pontos fixos opostos", formatPoints(r.OppositeFixedPoints))
+	fmt.Fprintf(&b, "  %-28s %d diferencial, %d linear\n", "branch number", r.DifferentialBranchNumber, r.LinearBranchNumber)
+	fmt.Fprintf(&b, "  %-28s", "avalanche por bit")
+	for _, a := range r.Avalanche {
+		fmt.Fprintf(&b, " %.2f", a)
+	}
+	fmt.Fprintf(&b, " (ideal %.1f)\n", float64(r.Out)/2)
+	fmt.Fprintf(&b, "  %-28s %.4f\n", "desvio máx. do SAC", r.SACDeviation)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func formatPoints(points []int) string {
+	if len(points) == 0 {
+		return "nenhum"
+	}
+	s := make([]string, len(points))
+	for i, p := range points {
+		s[i] = fmt.Sprintf("%02x", p)
+	}
+	return strings.Join(s, " ")
+}
+
+// WriteTable imprime uma DDT ou LAT em hexadecimal de colunas alinhadas.
+func WriteTable(w io.Writer, table [][]int) error {
+	var b strings.Builder
+	b.WriteString("     ")
+	for col := range table[0] {
+		fmt.Fprintf(&b, " %3x", col)
+	}
+	b.WriteString("\n")
+	for row, values := range table {
+		fmt.Fprintf(&b, "%4x:", row)
+		for _, v := range values {
+			fmt.Fprintf(&b, " %3d", v)
+		}
+		b.WriteString("\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
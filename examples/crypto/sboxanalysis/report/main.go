@@ -0,0 +1,52 @@
+/*
+
+Relatório das S-boxes do repositório
+
+	go run ./report            # resumo de todas as S-boxes
+	go run ./report -ddt aes   # também imprime a DDT de uma delas
+	go run ./report -lat des5  # também imprime a LAT de uma delas
+
+*/
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/osdeving/sboxanalysis"
+)
+
+type namedSBox struct {
+	name string
+	box  *sboxanalysis.SBox
+}
+
+func main() {
+	ddt := flag.String("ddt", "", "imprime a DDT da S-box com este nome")
+	lat := flag.String("lat", "", "imprime a LAT da S-box com este nome")
+	flag.Parse()
+
+	boxes := []namedSBox{{"aes", sboxanalysis.FromBytes(aesSbox)}}
+	for i, s := range desS {
+		boxes = append(boxes, namedSBox{fmt.Sprintf("des%d", i+1), sboxanalysis.FromDES(s)})
+	}
+	boxes = append(boxes, namedSBox{"rc2", sboxanalysis.FromBytes(piTable)})
+
+	for _, b := range boxes {
+		if err := sboxanalysis.Analyze(b.name, b.box).WriteText(os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		if b.name == *ddt {
+			fmt.Println("  DDT:")
+			sboxanalysis.WriteTable(os.Stdout, b.box.DDT())
+		}
+		if b.name == *lat {
+			fmt.Println("  LAT:")
+			sboxanalysis.WriteTable(os.Stdout, b.box.LAT())
+		}
+		fmt.Println()
+	}
+}
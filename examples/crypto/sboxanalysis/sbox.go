@@ -0,0 +1,318 @@
+/*
+
+Perfil Criptanalítico de S-boxes
+
+A S-box é a única parte não linear de cifras como AES e DES; a resistência
+contra criptoanálise diferencial e linear depende quase toda dela. Este
+pacote recebe qualquer tabela de n bits de entrada para m bits de saída
+(8→8 no AES e no PITABLE do RC2, 6→4 no DES) e calcula:
+
+	+-------------------------+-------------------------------------------------+
+	| Propriedade             | O que mede                                      |
+	+-------------------------+-------------------------------------------------+
+	| DDT                     | quantos x levam a diferença a na diferença b    |
+	| uniformidade diferencial| maior entrada da DDT com a != 0 (menor=melhor)  |
+	| LAT                     | viés de cada aproximação linear a·x = b·S(x)    |
+	| não linearidade         | distância até a função afim mais próxima        |
+	| grau algébrico          | maior grau da forma normal algébrica (ANF)      |
+	| pontos fixos            | S(x) = x e S(x) = ~x                            |
+	| branch number           | min wt(a) + wt(b) com a transição possível      |
+	| avalanche / SAC         | chance de cada bit de saída mudar com 1 bit     |
+	+-------------------------+-------------------------------------------------+
+
+Convenções:
+
+  - a entrada x é um inteiro de n bits; o bit i é (x >> i) & 1
+  - o produto escalar a·x é a paridade de a & x
+  - LAT[a][b] = #{x : a·x = b·S(x)} - 2^(n-1), ou seja, o viés vezes 2^n
+
+*/
+
+package sboxanalysis
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+var ErrTableSize = errors.New("sboxanalysis: tabela deve ter 2^n entradas")
+
+// SBox é uma tabela de In bits de entrada para Out bits de saída (até 8).
+type SBox struct {
+	In    int
+	Out   int
+	Table []byte
+}
+
+// New valida a tabela: 2^in entradas, todas menores que 2^out.
+func New(table []byte, in, out int) (*SBox, error) {
+	if in < 1 || in > 8 || out < 1 || out > 8 || len(table) != 1<<in {
+		return nil, ErrTableSize
+	}
+	for x, y := range table {
+		if int(y) >= 1<<out {
+			return nil, fmt.Errorf("sboxanalysis: S(%d) = %d não cabe em %d bits", x, y, out)
+		}
+	}
+	t := make([]byte, len(table))
+	copy(t, table)
+	return &SBox{In: in, Out: out, Table: t}, nil
+}
+
+// FromBytes cria uma S-box 8→8 (AES, PITABLE do RC2).
+func FromBytes(table [256]byte) *SBox {
+	s, _ := New(table[:], 8, 8)
+	return s
+}
+
+/*
+FromDES: Converte uma S-box do DES (4 linhas × 16 colunas) para uma tabela
+indexada diretamente pelos 6 bits de entrada b1..b6 (b1 = bit mais
+significativo). A linha é formada por b1b6 e a coluna por b2b3b4b5, igual
+à função f de des.go.
+*/
+func FromDES(table [64]uint8) *SBox {
+	t := make([]byte, 64)
+	for x := 0; x < 64; x++ {
+		row := ((x & 0x20) >> 4) | (x & 0x01)
+		col := (x >> 1) & 0x0F
+		t[x] = table[row*16+col]
+	}
+	return &SBox{In: 6, Out: 4, Table: t}
+}
+
+func dot(a, x int) int {
+	return bits.OnesCount(uint(a&x)) & 1
+}
+
+// DDT devolve a tabela de distribuição de diferenças, 2^In × 2^Out.
+func (s *SBox) DDT() [][]int {
+	ddt := make([][]int, 1<<s.In)
+	for a := range ddt {
+		ddt[a] = make([]int, 1<<s.Out)
+		for x := 0; x < 1<<s.In; x++ {
+			ddt[a][s.Table[x]^s.Table[x^a]]++
+		}
+	}
+	return ddt
+}
+
+// DifferentialUniformity é o maior valor da DDT fora da linha a = 0.
+func (s *SBox) DifferentialUniformity() int {
+	best := 0
+	for a, row := range s.DDT() {
+		if a == 0 {
+			continue
+		}
+		for _, n := range row {
+			best = max(best, n)
+		}
+	}
+	return best
+}
+
+// LAT devolve a tabela de aproximações lineares (contagem - 2^(In-1)).
+func (s *SBox) LAT() [][]int {
+	lat := make([][]int, 1<<s.In)
+	for a := range lat {
+		lat[a] = make([]int, 1<<s.Out)
+		for b := range lat[a] {
+			n := 0
+			for x := 0; x < 1<<s.In; x++ {
+				if dot(a, x) == dot(b, int(s.Table[x])) {
+					n++
+				}
+			}
+			lat[a][b] = n - 1<<(s.In-1)
+		}
+	}
+	return lat
+}
+
+/*
+Nonlinearity: 2^(n-1) - max |LAT[a][b]| com b != 0.
+
+	É o menor número de entradas que seria preciso alterar em alguma
+	combinação b·S(x) para que ela virasse uma função afim. Para 8 bits o
+	máximo conhecido para permutações é 112, atingido pelo AES.
+*/
+func (s *SBox) Nonlinearity() int {
+	worst := 0
+	for _, row := range s.LAT() {
+		for b := 1; b < len(row); b++ {
+			worst = max(worst, abs(row[b]))
+		}
+	}
+	return 1<<(s.In-1) - worst
+}
+
+/*
+ANF: Forma normal algébrica do bit de saída j (transformada de Möbius).
+anf[u] = 1 significa que o monômio prod_{i em u} x_i aparece no polinômio.
+*/
+func (s *SBox) ANF(j int) []byte {
+	anf := make([]byte, 1<<s.In)
+	for x := range anf {
+		anf[x] = (s.Table[x] >> j) & 1
+	}
+	for i := 0; i < s.In; i++ {
+		for x := range anf {
+			if x&(1<<i) != 0 {
+				anf[x] ^= anf[x^(1<<i)]
+			}
+		}
+	}
+	return anf
+}
+
+// Degrees devolve o grau algébrico de cada bit de saída.
+func (s *SBox) Degrees() []int {
+	degrees := make([]int, s.Out)
+	for j := range degrees {
+		for u, c := range s.ANF(j) {
+			if c == 1 {
+				degrees[j] = max(degrees[j], bits.OnesCount(uint(u)))
+			}
+		}
+	}
+	return degrees
+}
+
+// Degree é o maior grau entre os bits de saída.
+func (s *SBox) Degree() int {
+	d := 0
+	for _, dj := range s.Degrees() {
+		d = max(d, dj)
+	}
+	return d
+}
+
+// FixedPoints devolve os x com S(x) = x (só faz sentido se In == Out).
+func (s *SBox) FixedPoints() []int {
+	var fixed []int
+	if s.In != s.Out {
+		return fixed
+	}
+	for x, y := range s.Table {
+		if int(y) == x {
+			fixed = append(fixed, x)
+		}
+	}
+	return fixed
+}
+
+// OppositeFixedPoints devolve os x com S(x) = ~x (complemento de In bits).
+func (s *SBox) OppositeFixedPoints() []int {
+	var fixed []int
+	if s.In != s.Out {
+		return fixed
+	}
+	mask := 1<<s.In - 1
+	for x, y := range s.Table {
+		if int(y) == x^mask {
+			fixed = append(fixed, x)
+		}
+	}
+	return fixed
+}
+
+// DifferentialBranchNumber: min wt(a) + wt(b) com a != 0 e DDT[a][b] > 0.
+func (s *SBox) DifferentialBranchNumber() int {
+	best := s.In + s.Out
+	for a, row := range s.DDT() {
+		if a == 0 {
+			continue
+		}
+		for b, n := range row {
+			if n > 0 {
+				best = min(best, bits.OnesCount(uint(a))+bits.OnesCount(uint(b)))
+			}
+		}
+	}
+	return best
+}
+
+// LinearBranchNumber: min wt(a) + wt(b) com b != 0 e LAT[a][b] != 0.
+func (s *SBox) LinearBranchNumber() int {
+	best := s.In + s.Out
+	for a, row := range s.LAT() {
+		for b := 1; b < len(row); b++ {
+			if row[b] != 0 {
+				best = min(best, bits.OnesCount(uint(a))+bits.OnesCount(uint(b)))
+			}
+		}
+	}
+	return best
+}
+
+/*
+SAC: Matriz do critério de avalanche estrito. SAC[i][j] é a probabilidade
+do bit de saída j mudar quando o bit de entrada i é invertido; o ideal é
+0.5 em todas as posições.
+*/
+func (s *SBox) SAC() [][]float64 {
+	sac := make([][]float64, s.In)
+	for i := range sac {
+		sac[i] = make([]float64, s.Out)
+		for x := 0; x < 1<<s.In; x++ {
+			diff := s.Table[x] ^ s.Table[x^(1<<i)]
+			for j := 0; j < s.Out; j++ {
+				sac[i][j] += float64((diff >> j) & 1)
+			}
+		}
+		for j := range sac[i] {
+			sac[i][j] /= float64(int(1) << s.In)
+		}
+	}
+	return sac
+}
+
+// Avalanche devolve, para cada bit de entrada, a média de bits de saída
+// alterados (ideal: Out/2).
+func (s *SBox) Avalanche() []float64 {
+	avalanche := make([]float64, s.In)
+	for i, row := range s.SAC() {
+		for _, p := range row {
+			avalanche[i] += p
+		}
+	}
+	return avalanche
+}
+
+// SACDeviation é o maior |SAC[i][j] - 0.5|; 0 significa SAC perfeito.
+func (s *SBox) SACDeviation() float64 {
+	worst := 0.0
+	for _, row := range s.SAC() {
+		for _, p := range row {
+			d := p - 0.5
+			if d < 0 {
+				d = -d
+			}
+			worst = max(worst, d)
+		}
+	}
+	return worst
+}
+
+// IsPermutation diz se a S-box é bijetora (necessário para decifrar no AES).
+func (s *SBox) IsPermutation() bool {
+	if s.In != s.Out {
+		return false
+	}
+	seen := make([]bool, 1<<s.Out)
+	for _, y := range s.Table {
+		if seen[y] {
+			return false
+		}
+		seen[y] = true
+	}
+	return true
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,145 @@
+package sboxanalysis
+
+import (
+	"math/bits"
+	"testing"
+)
+
+// aesSbox gera a S-box do AES: inverso em GF(2^8) seguido da transformação afim.
+func aesSbox() [256]byte {
+	mul := func(a, b byte) byte {
+		var p byte
+		for b != 0 {
+			if b&1 != 0 {
+				p ^= a
+			}
+			hi := a & 0x80
+			a <<= 1
+			if hi != 0 {
+				a ^= 0x1b
+			}
+			b >>= 1
+		}
+		return p
+	}
+
+	var s [256]byte
+	for x := 0; x < 256; x++ {
+		var inv byte
+		for y := 1; y < 256 && x != 0; y++ {
+			if mul(byte(x), byte(y)) == 1 {
+				inv = byte(y)
+				break
+			}
+		}
+		s[x] = inv ^ bits.RotateLeft8(inv, 1) ^ bits.RotateLeft8(inv, 2) ^
+			bits.RotateLeft8(inv, 3) ^ bits.RotateLeft8(inv, 4) ^ 0x63
+	}
+	return s
+}
+
+// S5 do DES, usada por Matsui na aproximação linear de melhor viés.
+var desS5 = [64]uint8{
+	2, 12, 4, 1, 7, 10, 11, 6, 8, 5, 3, 15, 13, 0, 14, 9,
+	14, 11, 2, 12, 4, 7, 13, 1, 5, 0, 15, 10, 3, 9, 8, 6,
+	4, 2, 1, 11, 10, 13, 7, 8, 15, 9, 12, 5, 6, 3, 0, 14,
+	11, 8, 12, 7, 1, 14, 2, 13, 6, 15, 0, 9, 10, 4, 5, 3,
+}
+
+func TestAESSbox(t *testing.T) {
+	table := aesSbox()
+	if table[0x00] != 0x63 || table[0x53] != 0xed {
+		t.Fatalf("S-box gerada incorretamente: S(00)=%02x S(53)=%02x", table[0x00], table[0x53])
+	}
+	s := FromBytes(table)
+
+	if !s.IsPermutation() {
+		t.Error("S-box do AES deveria ser permutação")
+	}
+	if got := s.DifferentialUniformity(); got != 4 {
+		t.Errorf("uniformidade diferencial = %d, esperado 4", got)
+	}
+	if got := s.Nonlinearity(); got != 112 {
+		t.Errorf("não linearidade = %d, esperado 112", got)
+	}
+	if got := s.Degree(); got != 7 {
+		t.Errorf("grau algébrico = %d, esperado 7", got)
+	}
+	if len(s.FixedPoints()) != 0 || len(s.OppositeFixedPoints()) != 0 {
+		t.Errorf("pontos fixos: %v, opostos: %v", s.FixedPoints(), s.OppositeFixedPoints())
+	}
+}
+
+func TestDESS5Matsui(t *testing.T) {
+	s := FromDES(desS5)
+
+	// NS5(16, 15) = 12 em Matsui (1993): 12 - 32 = -20
+	if got := s.LAT()[0x10][0xf]; got != -20 {
+		t.Errorf("LAT[10][f] = %d, esperado -20", got)
+	}
+	if got := s.Nonlinearity(); got != 12 {
+		t.Errorf("não linearidade = %d, esperado 12", got)
+	}
+}
+
+func TestDESCriterioUmBit(t *testing.T) {
+	// Critério de projeto do DES: inverter um bit de entrada muda pelo menos
+	// dois bits de saída.
+	ddt := FromDES(desS5).DDT()
+	for i := 0; i < 6; i++ {
+		for b, n := range ddt[1<<i] {
+			if n > 0 && bits.OnesCount(uint(b)) < 2 {
+				t.Errorf("DDT[%02x][%x] = %d", 1<<i, b, n)
+			}
+		}
+	}
+	for a, row := range ddt {
+		sum := 0
+		for _, n := range row {
+			sum += n
+		}
+		if sum != 64 {
+			t.Errorf("linha %02x da DDT soma %d", a, sum)
+		}
+	}
+}
+
+func TestIdentidade(t *testing.T) {
+	table := make([]byte, 16)
+	for x := range table {
+		table[x] = byte(x)
+	}
+	s, err := New(table, 4, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := s.Nonlinearity(); got != 0 {
+		t.Errorf("não linearidade = %d, esperado 0", got)
+	}
+	if got := s.Degree(); got != 1 {
+		t.Errorf("grau = %d, esperado 1", got)
+	}
+	if got := len(s.FixedPoints()); got != 16 {
+		t.Errorf("pontos fixos = %d, esperado 16", got)
+	}
+	if got := s.DifferentialUniformity(); got != 16 {
+		t.Errorf("uniformidade diferencial = %d, esperado 16", got)
+	}
+	for i, row := range s.SAC() {
+		for j, p := range row {
+			if want := map[bool]float64{true: 1, false: 0}[i == j]; p != want {
+				t.Errorf("SAC[%d][%d] = %v, esperado %v", i, j, p, want)
+			}
+		}
+	}
+}
+
+func TestNewInvalido(t *testing.T) {
+	if _, err := New(make([]byte, 10), 4, 4); err != ErrTableSize {
+		t.Errorf("tamanho errado: err = %v", err)
+	}
+	if _, err := New([]byte{0, 1, 2, 4}, 2, 2); err == nil {
+		t.Error("esperado erro para saída maior que 2 bits")
+	}
+}